@@ -0,0 +1,177 @@
+package currconv
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Period is the length of an accounting period.
+type Period int
+
+const (
+	// Monthly is a calendar month.
+	Monthly Period = iota
+	// Quarterly is a calendar quarter.
+	Quarterly
+)
+
+// defaultAccountingPlaces is the default precision of averages, same as the rates returned by CurrencyConverterAPI.
+const defaultAccountingPlaces = 6
+
+// AccountingRatesRequest contains request fields of AccountingRates.
+type AccountingRatesRequest struct {
+	// Q is the same with ConvertRequest's Q.
+	Q []string
+	// Date and EndDate form a date range, every period overlapping the range is returned in full.
+	Date    time.Time
+	EndDate time.Time
+	// Period is the length of each accounting period.
+	Period Period
+	// Places is the number of digits after the decimal point of averages, default to 6.
+	Places int32
+}
+
+// AccountingRate contains the average and closing rates of a pair in a period.
+type AccountingRate struct {
+	Pair string
+	// Period is "2006-01" for Monthly, or "2006-Q1" for Quarterly.
+	Period string
+	// Start and End are the first and the last day of the period.
+	Start time.Time
+	End   time.Time
+	// Average is the simple average of every available daily rate in the period.
+	Average Decimal
+	// BusinessDayAverage is the average of the daily rates from Monday to Friday, public holidays are not excluded.
+	// It is 0 if BusinessDays is 0.
+	BusinessDayAverage Decimal
+	// BusinessDays is the number of daily rates from Monday to Friday, used by BusinessDayAverage.
+	BusinessDays int
+	// Closing is the last available rate in the period, at ClosingDate.
+	Closing     Decimal
+	ClosingDate time.Time
+	// DailyRates are the rates used to compute the average and closing rates, sorted by date.
+	DailyRates []DailyRate
+}
+
+// DailyRate is a rate of a single day.
+type DailyRate struct {
+	Date        time.Time
+	Rate        Decimal
+	BusinessDay bool
+}
+
+// AccountingRates returns the average and closing rates of each pair in each period overlapping the requested date range.
+// Rates are fetched with ConvertHistoricalCompactExact up to today, periods without any available rate are omitted.
+// The result is sorted by the order of `req.Q` and then by period.
+func (a *API) AccountingRates(req AccountingRatesRequest) (result []AccountingRate, err error) {
+	a, span := a.startSpan("AccountingRates", convertHistoricalAttributes(ConvertHistoricalRequest{Q: req.Q, Date: req.Date, EndDate: req.EndDate})...)
+	defer func() { a.endSpan(span, err) }()
+
+	if len(req.Q) == 0 {
+		return nil, errors.New("`Q` require at least one currency conversion")
+	}
+
+	if req.Date.IsZero() {
+		return nil, errors.New("`Date` is required")
+	}
+
+	end := req.EndDate
+	if end.IsZero() {
+		end = req.Date
+	}
+
+	places := req.Places
+	if places == 0 {
+		places = defaultAccountingPlaces
+	}
+
+	start, _ := periodOf(req.Date, req.Period)
+	_, end = periodOf(end, req.Period)
+
+	// Rates after today do not exist yet, the current period is computed with the rates up to today.
+	fetchEnd := end
+	if now := time.Now().UTC(); fetchEnd.After(now) {
+		fetchEnd = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if fetchEnd.Before(start) {
+		return nil, nil
+	}
+
+	rates, err := a.ConvertHistoricalMany(ConvertHistoricalRequest{Q: req.Q, Date: start, EndDate: fetchEnd})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pair := range req.Q {
+		for ps := start; !ps.After(end); {
+			_, pe := periodOf(ps, req.Period)
+			if r, ok := accountingRate(pair, ps, pe, req.Period, rates[pair], places); ok {
+				result = append(result, r)
+			}
+			ps = pe.AddDate(0, 0, 1)
+		}
+	}
+
+	return result, nil
+}
+
+// accountingRate computes the AccountingRate of `pair` from `start` to `end` with daily `rates`.
+func accountingRate(pair string, start, end time.Time, period Period, rates map[string]Decimal, places int32) (AccountingRate, bool) {
+	r := AccountingRate{
+		Pair:   pair,
+		Period: periodName(start, period),
+		Start:  start,
+		End:    end,
+	}
+
+	var sum, businessSum Decimal
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		rate, ok := rates[formatDate(d)]
+		if !ok {
+			continue
+		}
+
+		business := d.Weekday() != time.Saturday && d.Weekday() != time.Sunday
+		r.DailyRates = append(r.DailyRates, DailyRate{Date: d, Rate: rate, BusinessDay: business})
+		sum = sum.Add(rate)
+		if business {
+			businessSum = businessSum.Add(rate)
+			r.BusinessDays++
+		}
+		r.Closing, r.ClosingDate = rate, d
+	}
+
+	if len(r.DailyRates) == 0 {
+		return AccountingRate{}, false
+	}
+
+	r.Average = sum.Div(NewDecimalFromInt(int64(len(r.DailyRates))), places)
+	if r.BusinessDays > 0 {
+		r.BusinessDayAverage = businessSum.Div(NewDecimalFromInt(int64(r.BusinessDays)), places)
+	}
+
+	return r, true
+}
+
+// periodOf returns the first and the last day of the period containing `t`.
+func periodOf(t time.Time, period Period) (time.Time, time.Time) {
+	month := t.Month()
+	months := 1
+	if period == Quarterly {
+		month = (month-1)/3*3 + 1
+		months = 3
+	}
+
+	start := time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, months, -1)
+}
+
+// periodName returns the name of the period starting at `start`.
+func periodName(start time.Time, period Period) string {
+	if period == Quarterly {
+		return fmt.Sprintf("%d-Q%d", start.Year(), (start.Month()-1)/3+1)
+	}
+
+	return start.Format("2006-01")
+}
@@ -0,0 +1,135 @@
+package currconv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPI_AccountingRates(t *testing.T) {
+	// Weekdays at 0.90 and weekends at 0.93, from 2023-01-01 to 2023-02-10.
+	rates := map[string]string{}
+	for d := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC); d.Before(time.Date(2023, 2, 11, 0, 0, 0, 0, time.UTC)); d = d.AddDate(0, 0, 1) {
+		rates[d.Format("2006-01-02")] = "0.90"
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			rates[d.Format("2006-01-02")] = "0.93"
+		}
+	}
+
+	var calls int32
+	ts := historicalServer(t, map[string]map[string]string{"USD_EUR": rates}, &calls)
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	result, err := api.AccountingRates(AccountingRatesRequest{
+		Q:       []string{"USD_EUR"},
+		Date:    time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+		EndDate: time.Date(2023, 3, 10, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	jan := result[0]
+	assert.Equal(t, "USD_EUR", jan.Pair)
+	assert.Equal(t, "2023-01", jan.Period)
+	assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), jan.Start)
+	assert.Equal(t, time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC), jan.End)
+	assert.Equal(t, "0.908710", jan.Average.String())
+	assert.Equal(t, "0.900000", jan.BusinessDayAverage.String())
+	assert.Equal(t, 22, jan.BusinessDays)
+	assert.Equal(t, "0.90", jan.Closing.String())
+	assert.Equal(t, time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC), jan.ClosingDate)
+	assert.Len(t, jan.DailyRates, 31)
+	assert.False(t, jan.DailyRates[0].BusinessDay)
+	assert.True(t, jan.DailyRates[1].BusinessDay)
+
+	feb := result[1]
+	assert.Equal(t, "2023-02", feb.Period)
+	assert.Equal(t, "0.906000", feb.Average.String())
+	assert.Equal(t, time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC), feb.ClosingDate)
+	assert.Len(t, feb.DailyRates, 10)
+
+	result, err = api.AccountingRates(AccountingRatesRequest{
+		Q:      []string{"USD_EUR"},
+		Date:   time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+		Period: Quarterly,
+		Places: 4,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "2023-Q1", result[0].Period)
+	assert.Equal(t, time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC), result[0].End)
+	assert.Equal(t, "0.9080", result[0].Average.String())
+	assert.Len(t, result[0].DailyRates, 41)
+}
+
+func TestAPI_AccountingRates_CurrentPeriod(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var calls int32
+	ts := historicalServer(t, map[string]map[string]string{"USD_EUR": {today.Format("2006-01-02"): "0.91"}}, &calls)
+	defer ts.Close()
+
+	var requested []string
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+		Hooks: Hooks{
+			OnRequest: func(info RequestInfo) {
+				requested = append(requested, info.Date, info.EndDate)
+			},
+		},
+	})
+
+	result, err := api.AccountingRates(AccountingRatesRequest{Q: []string{"USD_EUR"}, Date: now})
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "0.91", result[0].Closing.String())
+	assert.Equal(t, today, result[0].ClosingDate)
+	for _, date := range requested {
+		assert.LessOrEqual(t, date, today.Format("2006-01-02"), "dates after today are not requested")
+	}
+
+	calls = 0
+	result, err = api.AccountingRates(AccountingRatesRequest{Q: []string{"USD_EUR"}, Date: today.AddDate(0, 2, 0)})
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	assert.Zero(t, calls, "a period after today is not requested")
+}
+
+func TestAccountingRate_NoBusinessDay(t *testing.T) {
+	start, end := periodOf(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), Monthly)
+	r, ok := accountingRate("USD_EUR", start, end, Monthly, map[string]Decimal{
+		"2023-01-01": MustParseDecimal("0.93"),
+		"2023-01-07": MustParseDecimal("0.95"),
+	}, 6)
+	assert.True(t, ok)
+	assert.Equal(t, "0.940000", r.Average.String())
+	assert.Zero(t, r.BusinessDays, "weekend rates only have no business day average")
+	assert.True(t, r.BusinessDayAverage.IsZero())
+}
+
+func TestAPI_AccountingRatesError(t *testing.T) {
+	api := NewAPI(Config{
+		BaseURL: "/error/",
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	_, err := api.AccountingRates(AccountingRatesRequest{})
+	assert.EqualError(t, err, "`Q` require at least one currency conversion")
+
+	_, err = api.AccountingRates(AccountingRatesRequest{Q: []string{"USD_EUR"}})
+	assert.EqualError(t, err, "`Date` is required")
+
+	_, err = api.AccountingRates(AccountingRatesRequest{Q: []string{"USD_EUR"}, Date: time.Now()})
+	assert.Error(t, err)
+}
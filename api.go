@@ -1,11 +1,14 @@
 package currconv
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // Config of the API.
@@ -13,11 +16,40 @@ type Config struct {
 	BaseURL string
 	Version string
 	APIKey  string
+	// MaxPairsPerRequest is the maximum number of pairs of your plan in a single request, default to 2.
+	MaxPairsPerRequest int
+	// MaxHistoricalDays is the maximum number of days of your plan in a single historical date range, default to 8.
+	MaxHistoricalDays int
+	// UseDataset answers Currencies and Countries from the embedded dataset, without requesting CurrencyConverterAPI.
+	UseDataset bool
+	// Hooks run for every request sent to CurrencyConverterAPI.
+	Hooks Hooks
+	// Metrics collects metrics of every request sent to CurrencyConverterAPI, and the rates of its watched pairs.
+	Metrics *Metrics
+	// Logger logs every request sent to CurrencyConverterAPI, with the API key redacted. Nothing is logged if it is nil.
+	Logger *slog.Logger
+	// LogLevels are the levels of the records logged to Logger.
+	LogLevels LogLevels
+	// Tracer creates a span for every API method and every request sent to CurrencyConverterAPI.
+	Tracer Tracer
+	// CircuitBreaker fails requests fast while CurrencyConverterAPI is failing. Every request is sent if it is nil.
+	CircuitBreaker *CircuitBreaker
+	// Cache caches the current rates of ConvertCompact, ConvertCompactExact and ConvertMany. Every rate is fetched if it is nil.
+	Cache *RateCache
 }
 
+const (
+	defaultMaxPairsPerRequest = 2
+	defaultMaxHistoricalDays  = 8
+)
+
 // API is the wrapper implementation of CurrencyConverterAPI.
 type API struct {
 	config Config
+	ctx    context.Context
+	flight *flightGroup
+	// refreshCache fetches every rate and stores it to Config.Cache, without looking it up.
+	refreshCache bool
 }
 
 type Error struct {
@@ -28,12 +60,32 @@ type Error struct {
 // NewAPI create and return an API.
 func NewAPI(config Config) *API {
 	return &API{
-		config,
+		config: config,
+		flight: newFlightGroup(),
+	}
+}
+
+// maxPairsPerRequest returns the configured MaxPairsPerRequest, or the default of the free plan.
+func (a *API) maxPairsPerRequest() int {
+	if a.config.MaxPairsPerRequest > 0 {
+		return a.config.MaxPairsPerRequest
 	}
+
+	return defaultMaxPairsPerRequest
+}
+
+// maxHistoricalDays returns the configured MaxHistoricalDays, or the default of the free plan.
+func (a *API) maxHistoricalDays() int {
+	if a.config.MaxHistoricalDays > 0 {
+		return a.config.MaxHistoricalDays
+	}
+
+	return defaultMaxHistoricalDays
 }
 
 type response interface {
-	Convert | ConvertCompact | ConvertHistorical | ConvertHistoricalCompact | Currency | Country | Usage
+	Convert | ConvertCompact | ConvertHistorical | ConvertHistoricalCompact | Currency | Country | Usage |
+		ConvertExact | ConvertCompactExact | ConvertHistoricalExact | ConvertHistoricalCompactExact | compactRates
 }
 
 // call is a function used by all APIs to request CurrencyConverterAPI.
@@ -60,27 +112,87 @@ func call[T response](a *API, shouldPrefixAPIPath bool, path string, handler fun
 
 	u.RawQuery = query.Encode()
 
-	resp, err := http.Get(u.String())
+	if a.flight == nil {
+		result, _, err = roundTrip[T](a, path, u)
+		return result, err
+	}
+
+	body, leader, err := a.flight.do(a.context(), u.String(), func() ([]byte, error) {
+		var body []byte
+		result, body, err = roundTrip[T](a, path, u)
+		return body, err
+	})
+	if leader {
+		return result, err
+	}
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) && a.context().Err() == nil {
+			// The request was canceled by the caller sharing it, not by this one.
+			result, _, err = roundTrip[T](a, path, u)
+			return result, err
+		}
+
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &result)
 	if err != nil {
 		return nil, err
 	}
 
+	return result, nil
+}
+
+// roundTrip sends a request to `u` and decodes its response, returning the response body alongside.
+func roundTrip[T response](a *API, path string, u *url.URL) (result *T, body []byte, err error) {
+	req, err := http.NewRequestWithContext(a.context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = a.allowCircuit()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := requestInfo(path, u)
+	defer func() { a.recordCircuit(info.StatusCode, err) }()
+
+	if a.config.Hooks.OnRequest != nil {
+		a.config.Hooks.OnRequest(info)
+	}
+
+	span := a.startAttempt(info, req.Header)
+	defer func() { a.endAttempt(span, info, err) }()
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, nil, a.finish(info, start, nil, err)
+	}
+
+	info.StatusCode = resp.StatusCode
 	if resp.StatusCode != http.StatusOK {
-		return nil, parseError(resp)
+		return nil, nil, a.finish(info, start, nil, parseError(resp))
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err = io.ReadAll(resp.Body)
 	defer resp.Body.Close()
 	if err != nil {
-		return nil, err
+		return nil, nil, a.finish(info, start, nil, err)
 	}
 
 	err = json.Unmarshal(body, &result)
 	if err != nil {
-		return nil, err
+		return nil, nil, a.finish(info, start, body, err)
+	}
+
+	if a.config.Metrics != nil {
+		a.config.Metrics.observeResult(result)
 	}
 
-	return
+	return result, body, a.finish(info, start, body, nil)
 }
 
 // parseError uses `json.Unmarshal` to returns Error whenever it is possible.
@@ -0,0 +1,263 @@
+package currconv
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrRateNotFound is returned when no rate is available for a pair at a date.
+var ErrRateNotFound = errors.New("rate not found")
+
+// HistoricalAmount is an amount in Currency at its transaction Date.
+type HistoricalAmount struct {
+	Amount   Decimal
+	Currency string
+	Date     time.Time
+}
+
+// FallbackPolicy decides which rate to use when a date has no rate.
+type FallbackPolicy struct {
+	// LookbackDays is the maximum number of days to look back for the previous available rate.
+	// Zero disables the fallback, rows without a rate at their own date fail with ErrRateNotFound.
+	LookbackDays int
+}
+
+// ConvertHistoricalBulkRequest contains request fields of ConvertHistoricalBulk.
+type ConvertHistoricalBulkRequest struct {
+	// To is the currency to convert all rows into.
+	To string
+	// Rows are the amounts to convert, each at the rate of its own date.
+	Rows []HistoricalAmount
+	// Fallback is used when a row's date has no rate.
+	Fallback FallbackPolicy
+}
+
+// ConvertedAmount is a row of ConvertHistoricalBulkRequest converted into the target currency.
+type ConvertedAmount struct {
+	HistoricalAmount
+	// Converted is Amount * Rate, not rounded.
+	Converted Decimal
+	// Rate is the rate used to convert Amount.
+	Rate Decimal
+	// RateDate is the date of Rate, which is before Date if the fallback was used.
+	RateDate time.Time
+	// Err is the reason the row could not be converted.
+	Err error
+}
+
+// historicalWindow is a date range of a single historical request.
+type historicalWindow struct {
+	start string
+	end   string
+}
+
+// ConvertHistoricalBulk converts each row into `req.To` at the rate of the row's date.
+// Rows are grouped by pair and date windows so that rates are fetched with ConvertHistoricalCompactExact in as few calls as
+// possible, within MaxPairsPerRequest and MaxHistoricalDays.
+// The result is in the same order as `req.Rows`, a row that cannot be converted has its Err set, e.g. the error of the
+// request of its date window.
+func (a *API) ConvertHistoricalBulk(req ConvertHistoricalBulkRequest) (result []ConvertedAmount, err error) {
+	a, span := a.startSpan("ConvertHistoricalBulk", Attribute{AttributeRows, len(req.Rows)})
+	defer func() { a.endSpan(span, err) }()
+
+	if req.To == "" {
+		return nil, errors.New("`To` is required")
+	}
+
+	wanted := map[string]map[string]bool{}
+	for _, row := range req.Rows {
+		if row.Currency == req.To || row.Date.IsZero() {
+			continue
+		}
+
+		addDate(wanted, row.Currency+"_"+req.To, formatDate(row.Date))
+	}
+
+	rates := map[string]map[string]Decimal{}
+	fetched := map[string]map[string]bool{}
+	failed := map[string]map[string]error{}
+	_ = a.fetchHistoricalRates(wanted, rates, fetched, failed)
+
+	if req.Fallback.LookbackDays > 0 {
+		lookback := map[string]map[string]bool{}
+		for pair, dates := range wanted {
+			for date := range dates {
+				if _, ok := rates[pair][date]; ok || failed[pair][date] != nil {
+					continue
+				}
+
+				d, _ := time.Parse("2006-01-02", date)
+				for i := 1; i <= req.Fallback.LookbackDays; i++ {
+					prev := formatDate(d.AddDate(0, 0, -i))
+					if !fetched[pair][prev] && failed[pair][prev] == nil {
+						addDate(lookback, pair, prev)
+					}
+				}
+			}
+		}
+
+		_ = a.fetchHistoricalRates(lookback, rates, fetched, failed)
+	}
+
+	result = make([]ConvertedAmount, len(req.Rows))
+	for i, row := range req.Rows {
+		result[i] = convertRow(row, req.To, rates, failed, req.Fallback)
+	}
+
+	return result, nil
+}
+
+// convertRow converts `row` with the fetched `rates`, or fails with the error of a date in `failed`.
+func convertRow(row HistoricalAmount, to string, rates map[string]map[string]Decimal, failed map[string]map[string]error, fallback FallbackPolicy) ConvertedAmount {
+	c := ConvertedAmount{HistoricalAmount: row}
+
+	if row.Date.IsZero() {
+		c.Err = errors.New("`Date` is required")
+		return c
+	}
+
+	date := time.Date(row.Date.Year(), row.Date.Month(), row.Date.Day(), 0, 0, 0, 0, time.UTC)
+	if row.Currency == to {
+		c.Rate, c.RateDate, c.Converted = MustParseDecimal("1"), date, row.Amount
+		return c
+	}
+
+	pair := row.Currency + "_" + to
+	for i := 0; i <= fallback.LookbackDays; i++ {
+		d := date.AddDate(0, 0, -i)
+		if rate, ok := rates[pair][formatDate(d)]; ok {
+			c.Rate, c.RateDate, c.Converted = rate, d, row.Amount.Mul(rate)
+			return c
+		}
+
+		// The rate of a failed date is unknown, falling back to an older rate could use the wrong one.
+		if err := failed[pair][formatDate(d)]; err != nil {
+			c.Err = err
+			return c
+		}
+	}
+
+	c.Err = ErrRateNotFound
+	return c
+}
+
+// fetchHistoricalRates fetches `wanted` dates of each pair into `rates`, and marks requested dates in `fetched`.
+// The dates of a failed request are set to its error in `failed`, and the other requests go on.
+// If `failed` is nil, it stops at the first failed request and returns its error.
+func (a *API) fetchHistoricalRates(wanted map[string]map[string]bool, rates map[string]map[string]Decimal, fetched map[string]map[string]bool, failed map[string]map[string]error) error {
+	pairs := make([]string, 0, len(wanted))
+	for pair := range wanted {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+
+	// Pairs of a chunk share requests, with windows covering the union of their dates.
+	for _, q := range chunk(pairs, a.maxPairsPerRequest()) {
+		union := map[string]bool{}
+		for _, pair := range q {
+			for date := range wanted[pair] {
+				union[date] = true
+			}
+		}
+
+		for _, w := range historicalWindows(union, a.maxHistoricalDays()) {
+			req := ConvertHistoricalRequest{Q: q}
+			req.Date, _ = time.Parse("2006-01-02", w.start)
+			if w.end != w.start {
+				req.EndDate, _ = time.Parse("2006-01-02", w.end)
+			}
+
+			r, err := a.ConvertHistoricalCompactExact(req)
+			if err != nil && failed == nil {
+				return err
+			}
+			if err != nil {
+				for _, pair := range q {
+					for d := req.Date; !d.After(maxTime(req.Date, req.EndDate)); d = d.AddDate(0, 0, 1) {
+						if failed[pair] == nil {
+							failed[pair] = map[string]error{}
+						}
+						failed[pair][formatDate(d)] = err
+					}
+				}
+				continue
+			}
+
+			for _, pair := range q {
+				for d := req.Date; !d.After(maxTime(req.Date, req.EndDate)); d = d.AddDate(0, 0, 1) {
+					addDate(fetched, pair, formatDate(d))
+				}
+
+				for date, rate := range r[pair] {
+					if rates[pair] == nil {
+						rates[pair] = map[string]Decimal{}
+					}
+					rates[pair][date] = rate
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// historicalWindows covers `dates` with the fewest windows of at most `maxDays` days.
+func historicalWindows(dates map[string]bool, maxDays int) []historicalWindow {
+	sorted := make([]string, 0, len(dates))
+	for d := range dates {
+		sorted = append(sorted, d)
+	}
+	sort.Strings(sorted)
+
+	var windows []historicalWindow
+	for i := 0; i < len(sorted); {
+		start, _ := time.Parse("2006-01-02", sorted[i])
+		limit := formatDate(start.AddDate(0, 0, maxDays-1))
+
+		w := historicalWindow{start: sorted[i], end: sorted[i]}
+		for ; i < len(sorted) && sorted[i] <= limit; i++ {
+			w.end = sorted[i]
+		}
+
+		windows = append(windows, w)
+	}
+
+	return windows
+}
+
+// chunk splits `s` into slices of at most `size` elements, or returns `s` as a single slice if `size` is not positive.
+func chunk(s []string, size int) [][]string {
+	if size <= 0 {
+		return [][]string{s}
+	}
+
+	var chunks [][]string
+	for size < len(s) {
+		s, chunks = s[size:], append(chunks, s[:size])
+	}
+
+	return append(chunks, s)
+}
+
+// addDate adds `date` of `pair` into `m`.
+func addDate(m map[string]map[string]bool, pair string, date string) {
+	if m[pair] == nil {
+		m[pair] = map[string]bool{}
+	}
+	m[pair][date] = true
+}
+
+// formatDate formats `t` in the date format of CurrencyConverterAPI.
+func formatDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// maxTime returns the later of `a` and `b`.
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+
+	return a
+}
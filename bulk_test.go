@@ -0,0 +1,184 @@
+package currconv
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// historicalServer serves compact historical rates from `rates`, keyed by pair and date.
+func historicalServer(t *testing.T, rates map[string]map[string]string, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+
+		q := r.URL.Query()
+		start, err := time.Parse("2006-01-02", q.Get("date"))
+		assert.NoError(t, err)
+		end := start
+		if q.Get("endDate") != "" {
+			end, err = time.Parse("2006-01-02", q.Get("endDate"))
+			assert.NoError(t, err)
+		}
+
+		assert.LessOrEqual(t, len(strings.Split(q.Get("q"), ",")), 2)
+		assert.Less(t, end.Sub(start), 8*24*time.Hour)
+
+		result := map[string]map[string]json.RawMessage{}
+		for _, pair := range strings.Split(q.Get("q"), ",") {
+			result[pair] = map[string]json.RawMessage{}
+			for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+				if rate, ok := rates[pair][d.Format("2006-01-02")]; ok {
+					result[pair][d.Format("2006-01-02")] = json.RawMessage(rate)
+				}
+			}
+		}
+
+		b, _ := json.Marshal(result)
+		_, _ = w.Write(b)
+	}))
+}
+
+func TestAPI_ConvertHistoricalBulk(t *testing.T) {
+	var calls int32
+	ts := historicalServer(t, map[string]map[string]string{
+		"USD_EUR": {
+			"2023-02-01": "0.91",
+			"2023-02-03": "0.92",
+			"2023-02-20": "0.93",
+		},
+		"MYR_EUR": {
+			"2023-02-01": "0.2151",
+			"2023-02-03": "0.2152",
+		},
+	}, &calls)
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	date := func(day int) time.Time {
+		return time.Date(2023, 2, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	rows := []HistoricalAmount{
+		{MustParseDecimal("100"), "USD", date(1)},
+		{MustParseDecimal("10.50"), "MYR", date(3)},
+		{MustParseDecimal("200"), "USD", date(3)},
+		{MustParseDecimal("1"), "USD", date(20)},
+		{MustParseDecimal("5"), "EUR", date(2)},
+		{MustParseDecimal("7"), "MYR", date(2)},
+	}
+
+	result, err := api.ConvertHistoricalBulk(ConvertHistoricalBulkRequest{To: "EUR", Rows: rows})
+	assert.NoError(t, err)
+	assert.Len(t, result, len(rows))
+
+	// USD and MYR share the 02-01 ~ 02-03 window, USD has another window at 02-20.
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	assert.Equal(t, "91.00", result[0].Converted.String())
+	assert.Equal(t, date(1), result[0].RateDate)
+	assert.Equal(t, "2.259600", result[1].Converted.String())
+	assert.Equal(t, "0.2152", result[1].Rate.String())
+	assert.Equal(t, "184.00", result[2].Converted.String())
+	assert.Equal(t, "0.93", result[3].Converted.String())
+	assert.Equal(t, "5", result[4].Converted.String())
+	assert.Equal(t, "1", result[4].Rate.String())
+	assert.ErrorIs(t, result[5].Err, ErrRateNotFound)
+	assert.Equal(t, rows[5], result[5].HistoricalAmount)
+
+	atomic.StoreInt32(&calls, 0)
+	result, err = api.ConvertHistoricalBulk(ConvertHistoricalBulkRequest{
+		To:       "EUR",
+		Rows:     rows[5:],
+		Fallback: FallbackPolicy{LookbackDays: 3},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, result[0].Err)
+	assert.Equal(t, date(1), result[0].RateDate)
+	assert.Equal(t, "1.5057", result[0].Converted.String())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestAPI_ConvertHistoricalBulkError(t *testing.T) {
+	api := NewAPI(Config{
+		BaseURL: "/error/",
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	_, err := api.ConvertHistoricalBulk(ConvertHistoricalBulkRequest{})
+	assert.EqualError(t, err, "`To` is required")
+
+	result, err := api.ConvertHistoricalBulk(ConvertHistoricalBulkRequest{
+		To:   "EUR",
+		Rows: []HistoricalAmount{{MustParseDecimal("1"), "USD", time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)}},
+	})
+	assert.NoError(t, err)
+	assert.Error(t, result[0].Err, "a failed request fails its rows")
+
+	result, err = api.ConvertHistoricalBulk(ConvertHistoricalBulkRequest{
+		To:   "EUR",
+		Rows: []HistoricalAmount{{Amount: MustParseDecimal("1"), Currency: "USD"}},
+	})
+	assert.NoError(t, err)
+	assert.EqualError(t, result[0].Err, "`Date` is required")
+}
+
+func TestAPI_ConvertHistoricalBulk_FailedWindow(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("date") == "2023-02-20" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"status": 500, "error": "Internal error"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"USD_EUR": {"2023-02-01": 0.91, "2023-02-10": 0.92}}`))
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{BaseURL: ts.URL, APIKey: "key", Version: "v1"})
+
+	date := func(day int) time.Time { return time.Date(2023, 2, day, 0, 0, 0, 0, time.UTC) }
+	result, err := api.ConvertHistoricalBulk(ConvertHistoricalBulkRequest{
+		To: "EUR",
+		Rows: []HistoricalAmount{
+			{MustParseDecimal("10"), "USD", date(1)},
+			{MustParseDecimal("10"), "USD", date(20)},
+			{MustParseDecimal("10"), "USD", date(21)},
+		},
+		Fallback: FallbackPolicy{LookbackDays: 15},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, result[0].Err)
+	assert.Equal(t, "9.10", result[0].Converted.String(), "rows of other windows are converted")
+	assert.EqualError(t, result[1].Err, "Internal error")
+	assert.EqualError(t, result[2].Err, "Internal error", "a failed date is not skipped by the fallback")
+}
+
+func TestHistoricalWindows(t *testing.T) {
+	dates := map[string]bool{
+		"2023-01-01": true,
+		"2023-01-05": true,
+		"2023-01-08": true,
+		"2023-01-09": true,
+		"2023-03-01": true,
+	}
+
+	assert.Equal(t, []historicalWindow{
+		{"2023-01-01", "2023-01-08"},
+		{"2023-01-09", "2023-01-09"},
+		{"2023-03-01", "2023-03-01"},
+	}, historicalWindows(dates, 8))
+
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, chunk([]string{"a", "b", "c"}, 2))
+	assert.Equal(t, [][]string{{"a", "b", "c"}}, chunk([]string{"a", "b", "c"}, 0))
+}
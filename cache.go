@@ -0,0 +1,276 @@
+package currconv
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSoftTTL    = 30 * time.Minute
+	defaultHardTTL    = time.Hour
+	defaultBatchDelay = 10 * time.Millisecond
+)
+
+// RateCache caches the current rates of ConvertCompact, ConvertCompactExact and ConvertMany, refreshing them ahead of expiry.
+// A rate older than SoftTTL is returned immediately and refreshed in the background, a rate older than HardTTL is fetched
+// before returning. Set SoftTTL to HardTTL or above for a plain TTL cache.
+// Set it as Config.Cache, it can be shared by multiple API with the same Config. The zero value is an empty RateCache with defaults.
+type RateCache struct {
+	// SoftTTL is the age of a rate after which it is refreshed in the background, default to 30 minutes.
+	SoftTTL time.Duration
+	// HardTTL is the age of a rate after which it is not returned anymore, default to 1 hour.
+	HardTTL time.Duration
+	// BatchDelay is how long a background refresh waits to batch the pairs becoming stale together, default to 10 milliseconds.
+	BatchDelay time.Duration
+	// MinRefreshInterval is the minimum duration between two background refresh requests, to stay within the rate limit of your plan.
+	MinRefreshInterval time.Duration
+
+	mu          sync.Mutex
+	rates       map[string]cacheEntry
+	pending     map[string]bool
+	refreshing  bool
+	lastRefresh time.Time
+	now         func() time.Time
+	sleep       func(d time.Duration)
+}
+
+// cacheEntry is a raw rate and the time it was fetched.
+type cacheEntry struct {
+	rate      json.RawMessage
+	fetchedAt time.Time
+}
+
+// NewRateCache create and return an empty RateCache.
+func NewRateCache() *RateCache {
+	return &RateCache{
+		rates:   map[string]cacheEntry{},
+		pending: map[string]bool{},
+		now:     time.Now,
+		sleep:   time.Sleep,
+	}
+}
+
+// Len returns the number of cached rates, including expired ones.
+func (c *RateCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.rates)
+}
+
+// lookup adds the rates of `pairs` younger than HardTTL to `rates`, and returns the pairs to fetch.
+// Rates older than SoftTTL are returned with the pairs to refresh in the background.
+func (c *RateCache) lookup(pairs []string, rates compactRates) (missing []string, stale []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock()
+	for _, pair := range pairs {
+		r, ok := c.rates[pair]
+		age := now.Sub(r.fetchedAt)
+		if !ok || age >= c.hardTTL() {
+			missing = append(missing, pair)
+			continue
+		}
+
+		rates[pair] = r.rate
+		if age >= c.softTTL() {
+			stale = append(stale, pair)
+		}
+	}
+
+	return missing, stale
+}
+
+// store caches `rates` fetched at `fetchedAt`.
+func (c *RateCache) store(rates compactRates, fetchedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.initMaps()
+	for pair, rate := range rates {
+		if r, ok := c.rates[pair]; ok && r.fetchedAt.After(fetchedAt) {
+			continue
+		}
+		c.rates[pair] = cacheEntry{rate: rate, fetchedAt: fetchedAt}
+	}
+}
+
+// schedule queues `pairs` to be refreshed in the background with `a`, starting the refresh if it is not running.
+func (c *RateCache) schedule(a *API, pairs []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.initMaps()
+	for _, pair := range pairs {
+		c.pending[pair] = true
+	}
+
+	if !c.refreshing && len(c.pending) > 0 {
+		c.refreshing = true
+		go c.refresh(a.WithContext(context.Background()))
+	}
+}
+
+// refresh fetches the pending pairs in batches within MaxPairsPerRequest, at most once per MinRefreshInterval,
+// until no pair is pending. A pair failing to refresh keeps its cached rate, and is queued again when it is looked up.
+func (c *RateCache) refresh(a *API) {
+	c.pause(c.batchDelay())
+
+	for {
+		c.mu.Lock()
+		if len(c.pending) == 0 {
+			c.refreshing = false
+			c.mu.Unlock()
+			return
+		}
+
+		batch := make([]string, 0, a.maxPairsPerRequest())
+		for _, pair := range sortedKeys(c.pending) {
+			if len(batch) == a.maxPairsPerRequest() {
+				break
+			}
+			batch = append(batch, pair)
+			delete(c.pending, pair)
+		}
+
+		var wait time.Duration
+		if !c.lastRefresh.IsZero() {
+			wait = c.lastRefresh.Add(c.MinRefreshInterval).Sub(c.clock())
+		}
+		c.mu.Unlock()
+
+		if wait > 0 {
+			if a.config.Metrics != nil {
+				a.config.Metrics.ObserveLimiterWait(wait)
+			}
+			c.pause(wait)
+		}
+
+		c.mu.Lock()
+		c.lastRefresh = c.clock()
+		c.mu.Unlock()
+
+		fetchedAt := c.clock()
+		if rates, err := a.fetchRates(batch); err == nil {
+			c.store(rates, fetchedAt)
+		}
+	}
+}
+
+// initMaps allocates the maps of a RateCache not created by NewRateCache. The caller must hold c.mu.
+func (c *RateCache) initMaps() {
+	if c.rates == nil {
+		c.rates = map[string]cacheEntry{}
+	}
+	if c.pending == nil {
+		c.pending = map[string]bool{}
+	}
+}
+
+// clock returns the current time, from `now` if it is set.
+func (c *RateCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+
+	return time.Now()
+}
+
+// pause sleeps for `d`, with `sleep` if it is set.
+func (c *RateCache) pause(d time.Duration) {
+	if c.sleep != nil {
+		c.sleep(d)
+		return
+	}
+
+	time.Sleep(d)
+}
+
+func (c *RateCache) softTTL() time.Duration {
+	if c.SoftTTL > 0 {
+		return c.SoftTTL
+	}
+
+	return defaultSoftTTL
+}
+
+func (c *RateCache) hardTTL() time.Duration {
+	if c.HardTTL > 0 {
+		return c.HardTTL
+	}
+
+	return defaultHardTTL
+}
+
+func (c *RateCache) batchDelay() time.Duration {
+	if c.BatchDelay > 0 {
+		return c.BatchDelay
+	}
+
+	return defaultBatchDelay
+}
+
+// convertCompact returns the compact rates of `req.Q` from Config.Cache, fetching the missing ones with fetchRates.
+// `cacheHit` reports whether every rate was served from the cache. All rates are fetched and cached if API.refreshCache is set.
+func convertCompact[T ConvertCompact | ConvertCompactExact](a *API, req ConvertRequest) (result *T, cacheHit bool, err error) {
+	if len(req.Q) == 0 {
+		result, err = call[T](a, true, "convert", convertQuery(req, true))
+		return result, false, err
+	}
+
+	rates := compactRates{}
+	missing := req.Q
+
+	cache := a.config.Cache
+	if cache != nil && !a.refreshCache {
+		var stale []string
+		missing, stale = cache.lookup(req.Q, rates)
+		if len(stale) > 0 {
+			cache.schedule(a, stale)
+		}
+
+		if m := a.config.Metrics; m != nil {
+			for i := 0; i < len(req.Q)-len(missing); i++ {
+				m.ObserveCacheHit()
+			}
+			for range missing {
+				m.ObserveCacheMiss()
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		fetchedAt := time.Now()
+		if cache != nil {
+			fetchedAt = cache.clock()
+		}
+
+		fetched, err := a.fetchRates(missing)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if cache != nil {
+			cache.store(fetched, fetchedAt)
+		}
+
+		for pair, rate := range fetched {
+			rates[pair] = rate
+		}
+	}
+
+	b, err := json.Marshal(rates)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = json.Unmarshal(b, &result)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result, len(missing) == 0, nil
+}
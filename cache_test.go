@@ -0,0 +1,211 @@
+package currconv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testClock is a settable clock safe for concurrent use.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// rateServer serves compact rates of the requested pairs from `rates`, recording the `q` of each request.
+type rateServer struct {
+	mu       sync.Mutex
+	rates    map[string]string
+	received []string
+}
+
+func (s *rateServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query().Get("q")
+	s.received = append(s.received, q)
+
+	var parts []string
+	for _, pair := range strings.Split(q, ",") {
+		if rate, ok := s.rates[pair]; ok {
+			parts = append(parts, `"`+pair+`": `+rate)
+		}
+	}
+	_, _ = w.Write([]byte("{" + strings.Join(parts, ",") + "}"))
+}
+
+func (s *rateServer) set(pair, rate string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rates[pair] = rate
+}
+
+func (s *rateServer) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.received...)
+}
+
+func TestRateCache(t *testing.T) {
+	rs := &rateServer{rates: map[string]string{"USD_MYR": "4.348493", "EUR_MYR": "4.6809"}}
+	ts := httptest.NewServer(rs)
+	defer ts.Close()
+
+	clock := &testClock{now: time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)}
+	cache := NewRateCache()
+	cache.SoftTTL = 10 * time.Minute
+	cache.HardTTL = time.Hour
+	cache.now = clock.Now
+	cache.sleep = func(time.Duration) {}
+
+	metrics := NewMetrics()
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "secret",
+		Version: "v1",
+		Cache:   cache,
+		Metrics: metrics,
+	})
+
+	r, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR", "EUR_MYR"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ConvertCompact{"USD_MYR": 4.348493, "EUR_MYR": 4.6809}, r)
+	assert.Equal(t, []string{"USD_MYR,EUR_MYR"}, rs.requests())
+
+	clock.Add(5 * time.Minute)
+	rs.set("USD_MYR", "4.4")
+	exact, err := api.ConvertCompactExact(ConvertRequest{Q: []string{"USD_MYR"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "4.348493", exact["USD_MYR"].String())
+	assert.Len(t, rs.requests(), 1, "a fresh rate is served from the cache")
+
+	clock.Add(5 * time.Minute)
+	r, err = api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ConvertCompact{"USD_MYR": 4.348493}, r, "a stale rate is returned immediately")
+	assert.Eventually(t, func() bool {
+		r, _ := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+		return r["USD_MYR"] == 4.4
+	}, time.Second, time.Millisecond, "and refreshed in the background")
+	assert.Equal(t, []string{"USD_MYR,EUR_MYR", "USD_MYR"}, rs.requests())
+
+	clock.Add(time.Hour)
+	rs.set("USD_MYR", "4.5")
+	r, err = api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ConvertCompact{"USD_MYR": 4.5}, r, "an expired rate is fetched before returning")
+	assert.Equal(t, []string{"USD_MYR,EUR_MYR", "USD_MYR", "USD_MYR"}, rs.requests())
+
+	var b strings.Builder
+	_, _ = metrics.WriteTo(&b)
+	assert.Contains(t, b.String(), "currconv_cache_misses_total 3\n")
+	assert.NotContains(t, b.String(), "currconv_cache_hits_total 0\n")
+}
+
+func TestRateCache_Literal(t *testing.T) {
+	rs := &rateServer{rates: map[string]string{"USD_MYR": "4.348493"}}
+	ts := httptest.NewServer(rs)
+	defer ts.Close()
+
+	cache := &RateCache{SoftTTL: time.Nanosecond}
+	api := NewAPI(Config{BaseURL: ts.URL, APIKey: "secret", Version: "v1", Cache: cache})
+
+	for i := 0; i < 2; i++ {
+		r, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+		assert.NoError(t, err)
+		assert.Equal(t, ConvertCompact{"USD_MYR": 4.348493}, r)
+	}
+	assert.Eventually(t, func() bool { return len(rs.requests()) == 2 }, time.Second, time.Millisecond,
+		"a stale rate is refreshed in the background")
+
+	var b strings.Builder
+	_, err := cache.WriteTo(&b)
+	assert.NoError(t, err)
+
+	restored := &RateCache{}
+	_, err = restored.ReadFrom(strings.NewReader(b.String()))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, restored.Len())
+}
+
+func TestRateCache_Batch(t *testing.T) {
+	rs := &rateServer{rates: map[string]string{"USD_MYR": "4.348493", "EUR_MYR": "4.6809", "SGD_MYR": "3.2786"}}
+	ts := httptest.NewServer(rs)
+	defer ts.Close()
+
+	clock := &testClock{now: time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)}
+	var mu sync.Mutex
+	var sleeps []time.Duration
+	batched := make(chan struct{})
+
+	cache := NewRateCache()
+	cache.SoftTTL = 10 * time.Minute
+	cache.MinRefreshInterval = time.Minute
+	cache.now = clock.Now
+	cache.sleep = func(d time.Duration) {
+		if d == defaultBatchDelay {
+			<-batched
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		sleeps = append(sleeps, d)
+	}
+
+	metrics := NewMetrics()
+	api := NewAPI(Config{
+		BaseURL:            ts.URL,
+		APIKey:             "secret",
+		Version:            "v1",
+		MaxPairsPerRequest: 2,
+		Cache:              cache,
+		Metrics:            metrics,
+	})
+
+	_, err := api.ConvertMany(ConvertRequest{Q: []string{"USD_MYR", "EUR_MYR", "SGD_MYR"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"USD_MYR,EUR_MYR", "SGD_MYR"}, rs.requests())
+	assert.Equal(t, 3, cache.Len())
+
+	clock.Add(10 * time.Minute)
+	_, err = api.ConvertMany(ConvertRequest{Q: []string{"USD_MYR", "EUR_MYR", "SGD_MYR"}})
+	assert.NoError(t, err)
+	close(batched)
+
+	assert.Eventually(t, func() bool { return len(rs.requests()) == 4 }, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return !cache.refreshing
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, []string{"EUR_MYR,SGD_MYR", "USD_MYR"}, rs.requests()[2:], "stale pairs are refreshed in batches")
+	assert.Equal(t, []time.Duration{defaultBatchDelay, time.Minute}, sleeps, "refresh requests are MinRefreshInterval apart")
+
+	var b strings.Builder
+	_, _ = metrics.WriteTo(&b)
+	assert.Contains(t, b.String(), "currconv_rate_limiter_waits_total 1\n")
+	assert.Contains(t, b.String(), "currconv_rate_limiter_wait_seconds_total 60\n")
+}
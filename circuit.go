@@ -0,0 +1,297 @@
+package currconv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is matched by CircuitOpenError with errors.Is.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through, counting failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every request fast with CircuitOpenError until the cool-down period is over.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of trial requests through, to decide whether to close or reopen the breaker.
+	CircuitHalfOpen
+)
+
+const (
+	defaultFailureRate      = 0.5
+	defaultMinRequests      = 10
+	defaultFailureWindow    = time.Minute
+	defaultCoolDown         = 30 * time.Second
+	defaultHalfOpenRequests = 1
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitOpenError is returned without requesting CurrencyConverterAPI while the CircuitBreaker is open,
+// or half-open with all trial requests in flight.
+type CircuitOpenError struct {
+	// State is CircuitOpen or CircuitHalfOpen.
+	State CircuitState
+	// RetryAt is when the breaker lets a trial request through, zero if it is half-open.
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	if e.RetryAt.IsZero() {
+		return fmt.Sprintf("circuit breaker is %s", e.State)
+	}
+
+	return fmt.Sprintf("circuit breaker is %s until %s", e.State, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is reports whether `target` is ErrCircuitOpen.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreaker stops requests to CurrencyConverterAPI while it is failing, so that callers fail fast instead of waiting.
+// Network errors, 429 and 5xx responses are failures, other responses are successes.
+// Set it as Config.CircuitBreaker, it can be shared by multiple API. The zero value is a closed CircuitBreaker with defaults.
+type CircuitBreaker struct {
+	// FailureRate is the ratio of failures in Window which opens the breaker, default to 0.5.
+	FailureRate float64
+	// MinRequests is the number of requests in Window before FailureRate applies, default to 10.
+	MinRequests int
+	// Window is the duration of the consecutive windows in which failures are counted, default to 1 minute.
+	Window time.Duration
+	// CoolDown is the duration the breaker stays open before letting trial requests through, default to 30 seconds.
+	CoolDown time.Duration
+	// HalfOpenRequests is the number of trial requests which must all succeed to close the breaker, default to 1.
+	HalfOpenRequests int
+
+	mu          sync.Mutex
+	state       CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	trials      int
+	successes   int
+	now         func() time.Time
+}
+
+// circuitTransition is a change of CircuitState, reported to Hooks.OnCircuitChange.
+type circuitTransition struct {
+	from CircuitState
+	to   CircuitState
+}
+
+// NewCircuitBreaker create and return a closed CircuitBreaker.
+func NewCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{
+		now: time.Now,
+	}
+}
+
+// State returns the current state of the breaker.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && !b.clock().Before(b.openedAt.Add(b.coolDown())) {
+		return CircuitHalfOpen
+	}
+
+	return b.state
+}
+
+// allow returns CircuitOpenError if a request is not allowed, and the transition to half-open if the cool-down period is over.
+func (b *CircuitBreaker) allow() (*circuitTransition, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var t *circuitTransition
+	switch b.state {
+	case CircuitOpen:
+		retryAt := b.openedAt.Add(b.coolDown())
+		if b.clock().Before(retryAt) {
+			return nil, &CircuitOpenError{State: CircuitOpen, RetryAt: retryAt}
+		}
+
+		t = b.transition(CircuitHalfOpen)
+		fallthrough
+	case CircuitHalfOpen:
+		if b.trials >= b.halfOpenRequests() {
+			return t, &CircuitOpenError{State: CircuitHalfOpen}
+		}
+		b.trials++
+	}
+
+	return t, nil
+}
+
+// record counts the result of an allowed request, and returns the transition it caused, if any.
+func (b *CircuitBreaker) record(failed bool) *circuitTransition {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitHalfOpen:
+		if failed {
+			return b.transition(CircuitOpen)
+		}
+
+		b.successes++
+		if b.successes >= b.halfOpenRequests() {
+			return b.transition(CircuitClosed)
+		}
+	case CircuitClosed:
+		now := b.clock()
+		if now.Sub(b.windowStart) >= b.window() {
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+
+		b.requests++
+		if failed {
+			b.failures++
+		}
+
+		if b.requests >= b.minRequests() && float64(b.failures)/float64(b.requests) >= b.failureRate() {
+			return b.transition(CircuitOpen)
+		}
+	}
+
+	return nil
+}
+
+// release gives back the trial of an allowed request whose result is not counted, e.g. canceled by the caller.
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen && b.trials > 0 {
+		b.trials--
+	}
+}
+
+// transition changes the state to `to` and resets the counters of the new state. The caller must hold b.mu.
+func (b *CircuitBreaker) transition(to CircuitState) *circuitTransition {
+	t := &circuitTransition{from: b.state, to: to}
+
+	b.state = to
+	b.trials, b.successes = 0, 0
+	b.windowStart, b.requests, b.failures = b.clock(), 0, 0
+	if to == CircuitOpen {
+		b.openedAt = b.clock()
+	}
+
+	return t
+}
+
+// clock returns the current time, from `now` if it is set.
+func (b *CircuitBreaker) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+
+	return time.Now()
+}
+
+func (b *CircuitBreaker) failureRate() float64 {
+	if b.FailureRate > 0 {
+		return b.FailureRate
+	}
+
+	return defaultFailureRate
+}
+
+func (b *CircuitBreaker) minRequests() int {
+	if b.MinRequests > 0 {
+		return b.MinRequests
+	}
+
+	return defaultMinRequests
+}
+
+func (b *CircuitBreaker) window() time.Duration {
+	if b.Window > 0 {
+		return b.Window
+	}
+
+	return defaultFailureWindow
+}
+
+func (b *CircuitBreaker) coolDown() time.Duration {
+	if b.CoolDown > 0 {
+		return b.CoolDown
+	}
+
+	return defaultCoolDown
+}
+
+func (b *CircuitBreaker) halfOpenRequests() int {
+	if b.HalfOpenRequests > 0 {
+		return b.HalfOpenRequests
+	}
+
+	return defaultHalfOpenRequests
+}
+
+// isCircuitFailure reports whether a request with `statusCode` and `err` counts as a failure of CurrencyConverterAPI.
+func isCircuitFailure(statusCode int, err error) bool {
+	if statusCode == 0 {
+		return err != nil
+	}
+
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// allowCircuit asks Config.CircuitBreaker whether a request is allowed, and reports its transition to Hooks.
+func (a *API) allowCircuit() error {
+	b := a.config.CircuitBreaker
+	if b == nil {
+		return nil
+	}
+
+	t, err := b.allow()
+	a.reportCircuit(t)
+
+	return err
+}
+
+// recordCircuit records the result of an allowed request to Config.CircuitBreaker.
+// A request canceled by the caller is not counted.
+func (a *API) recordCircuit(statusCode int, err error) {
+	b := a.config.CircuitBreaker
+	if b == nil {
+		return
+	}
+
+	if statusCode == 0 && errors.Is(err, context.Canceled) {
+		b.release()
+		return
+	}
+
+	a.reportCircuit(b.record(isCircuitFailure(statusCode, err)))
+}
+
+// reportCircuit runs Hooks.OnCircuitChange with `t`, if any.
+func (a *API) reportCircuit(t *circuitTransition) {
+	if t != nil && a.config.Hooks.OnCircuitChange != nil {
+		a.config.Hooks.OnCircuitChange(t.from, t.to)
+	}
+}
@@ -0,0 +1,171 @@
+package currconv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var status, hits int32
+	atomic.StoreInt32(&status, http.StatusServiceUnavailable)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		_, _ = w.Write([]byte(`{"timestamp": "2023-02-15T02:05:49.988Z", "usage": 1}`))
+	}))
+	defer ts.Close()
+
+	now := time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker()
+	b.MinRequests = 4
+	b.CoolDown = 10 * time.Second
+	b.now = func() time.Time { return now }
+
+	var transitions []string
+	api := NewAPI(Config{
+		BaseURL:        ts.URL,
+		APIKey:         "secret",
+		CircuitBreaker: b,
+		Hooks: Hooks{
+			OnCircuitChange: func(from, to CircuitState) {
+				transitions = append(transitions, fmt.Sprintf("%s -> %s", from, to))
+			},
+		},
+	})
+
+	// 2 failures out of 3 requests are below MinRequests.
+	for _, s := range []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusTooManyRequests} {
+		atomic.StoreInt32(&status, int32(s))
+		_, _ = api.Usage()
+	}
+	assert.Equal(t, CircuitClosed, b.State())
+
+	atomic.StoreInt32(&status, http.StatusInternalServerError)
+	_, err := api.Usage()
+	assert.Error(t, err)
+	assert.Equal(t, CircuitOpen, b.State())
+	assert.Equal(t, []string{"closed -> open"}, transitions)
+
+	_, err = api.Usage()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.EqualError(t, err, "circuit breaker is open until 2023-02-15T02:00:10Z")
+	var open *CircuitOpenError
+	assert.True(t, errors.As(err, &open))
+	assert.Equal(t, now.Add(10*time.Second), open.RetryAt)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&hits), "an open breaker must not request CurrencyConverterAPI")
+
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, CircuitHalfOpen, b.State())
+
+	_, err = api.Usage()
+	assert.Error(t, err)
+	assert.Equal(t, CircuitOpen, b.State(), "a failed trial reopens the breaker")
+	assert.Equal(t, []string{"closed -> open", "open -> half-open", "half-open -> open"}, transitions)
+
+	_, err = api.Usage()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	now = now.Add(10 * time.Second)
+	atomic.StoreInt32(&status, http.StatusOK)
+	_, err = api.Usage()
+	assert.NoError(t, err)
+	assert.Equal(t, CircuitClosed, b.State())
+	assert.Equal(t, []string{"closed -> open", "open -> half-open", "half-open -> open", "open -> half-open", "half-open -> closed"}, transitions)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&hits))
+}
+
+func TestCircuitBreaker_ClientErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status": 400, "error": "Invalid API key"}`))
+	}))
+	defer ts.Close()
+
+	b := NewCircuitBreaker()
+	b.MinRequests = 2
+	api := NewAPI(Config{BaseURL: ts.URL, APIKey: "secret", CircuitBreaker: b})
+
+	for i := 0; i < 5; i++ {
+		_, err := api.Usage()
+		assert.EqualError(t, err, "Invalid API key")
+	}
+	assert.Equal(t, CircuitClosed, b.State())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 5; i++ {
+		_, err := api.WithContext(ctx).Usage()
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+	assert.Equal(t, CircuitClosed, b.State(), "requests canceled by the caller are not failures")
+}
+
+func TestCircuitBreaker_Window(t *testing.T) {
+	now := time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker()
+	b.MinRequests = 2
+	b.FailureRate = 0.6
+	b.Window = time.Minute
+	b.now = func() time.Time { return now }
+
+	assert.Nil(t, b.record(true))
+	now = now.Add(time.Minute)
+	assert.Nil(t, b.record(true), "failures of the previous window are not counted")
+	assert.Nil(t, b.record(false))
+	assert.Equal(t, &circuitTransition{CircuitClosed, CircuitOpen}, b.record(true))
+}
+
+func TestCircuitBreaker_HalfOpenRequests(t *testing.T) {
+	now := time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker()
+	b.MinRequests = 1
+	b.HalfOpenRequests = 2
+	b.now = func() time.Time { return now }
+
+	b.record(true)
+	now = now.Add(defaultCoolDown)
+
+	tr, err := b.allow()
+	assert.NoError(t, err)
+	assert.Equal(t, &circuitTransition{CircuitOpen, CircuitHalfOpen}, tr)
+	_, err = b.allow()
+	assert.NoError(t, err)
+
+	_, err = b.allow()
+	assert.EqualError(t, err, "circuit breaker is half-open")
+
+	b.release()
+	_, err = b.allow()
+	assert.NoError(t, err, "a released trial can be taken again")
+
+	assert.Nil(t, b.record(false))
+	assert.Equal(t, &circuitTransition{CircuitHalfOpen, CircuitClosed}, b.record(false))
+}
+
+func TestCircuitBreaker_Literal(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	b := &CircuitBreaker{MinRequests: 2, CoolDown: time.Minute}
+	api := NewAPI(Config{BaseURL: ts.URL, APIKey: "secret", Version: "v1", CircuitBreaker: b})
+
+	for i := 0; i < 2; i++ {
+		_, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+		assert.Error(t, err)
+	}
+	assert.Equal(t, CircuitOpen, b.State())
+
+	_, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
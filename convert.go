@@ -20,12 +20,22 @@ type Convert struct {
 	Query struct {
 		Count int `json:"count"`
 	} `json:"query"`
-	Results map[string]struct {
-		ID  string  `json:"id"`
-		Val float32 `json:"val"`
-		To  string  `json:"to"`
-		Fr  string  `json:"fr"`
-	} `json:"results"`
+	Results map[string]ConversionResult `json:"results"`
+}
+
+// ConversionResult is a single conversion entry of Convert.Results.
+type ConversionResult struct {
+	ID  string  `json:"id"`
+	Val float32 `json:"val"`
+	To  string  `json:"to"`
+	Fr  string  `json:"fr"`
+}
+
+// Rate returns the conversion rate of `pair`, in "[FROM]_[TO]" format.
+// The second return value reports whether `pair` exists in the result.
+func (c *Convert) Rate(pair string) (float32, bool) {
+	r, ok := c.Results[pair]
+	return r.Val, ok
 }
 
 // ConvertCompact is the compact result of the ConvertCompact API.
@@ -46,42 +56,112 @@ type ConvertHistorical struct {
 	Query struct {
 		Count int `json:"count"`
 	} `json:"query"`
-	Date    string `json:"date"`
-	EndDate string `json:"endDate,omitempty"`
-	Results map[string]struct {
-		ID  string             `json:"id"`
-		To  string             `json:"to"`
-		Fr  string             `json:"fr"`
-		Val map[string]float32 `json:"val"`
-	} `json:"results"`
+	Date    string                      `json:"date"`
+	EndDate string                      `json:"endDate,omitempty"`
+	Results map[string]HistoricalResult `json:"results"`
+}
+
+// HistoricalResult is a single conversion entry of ConvertHistorical.Results.
+// Val is keyed by date in "2006-01-02" format.
+type HistoricalResult struct {
+	ID  string             `json:"id"`
+	To  string             `json:"to"`
+	Fr  string             `json:"fr"`
+	Val map[string]float32 `json:"val"`
+}
+
+// Rate returns the conversion rate of `pair` at `date`.
+// The second return value reports whether the rate exists in the result.
+func (c *ConvertHistorical) Rate(pair string, date time.Time) (float32, bool) {
+	r, ok := c.Results[pair]
+	if !ok {
+		return 0, false
+	}
+
+	v, ok := r.Val[date.Format("2006-01-02")]
+	return v, ok
 }
 
 // ConvertHistoricalCompact is the compact result of ConvertHistoricalCompact API.
 type ConvertHistoricalCompact map[string]map[string]float32
 
+// ConvertExact is the result of the ConvertExact API, same as Convert but keeps rates exactly as sent.
+type ConvertExact struct {
+	Query struct {
+		Count int `json:"count"`
+	} `json:"query"`
+	Results map[string]ConversionResultExact `json:"results"`
+}
+
+// ConversionResultExact is a single conversion entry of ConvertExact.Results.
+type ConversionResultExact struct {
+	ID  string  `json:"id"`
+	Val Decimal `json:"val"`
+	To  string  `json:"to"`
+	Fr  string  `json:"fr"`
+}
+
+// Rate returns the exact conversion rate of `pair`, in "[FROM]_[TO]" format.
+// The second return value reports whether `pair` exists in the result.
+func (c *ConvertExact) Rate(pair string) (Decimal, bool) {
+	r, ok := c.Results[pair]
+	return r.Val, ok
+}
+
+// ConvertCompactExact is the compact result of the ConvertCompactExact API.
+type ConvertCompactExact map[string]Decimal
+
+// ConvertHistoricalExact is the result of ConvertHistoricalExact API, same as ConvertHistorical but keeps rates exactly as sent.
+type ConvertHistoricalExact struct {
+	Query struct {
+		Count int `json:"count"`
+	} `json:"query"`
+	Date    string                           `json:"date"`
+	EndDate string                           `json:"endDate,omitempty"`
+	Results map[string]HistoricalResultExact `json:"results"`
+}
+
+// HistoricalResultExact is a single conversion entry of ConvertHistoricalExact.Results.
+// Val is keyed by date in "2006-01-02" format.
+type HistoricalResultExact struct {
+	ID  string             `json:"id"`
+	To  string             `json:"to"`
+	Fr  string             `json:"fr"`
+	Val map[string]Decimal `json:"val"`
+}
+
+// Rate returns the exact conversion rate of `pair` at `date`.
+// The second return value reports whether the rate exists in the result.
+func (c *ConvertHistoricalExact) Rate(pair string, date time.Time) (Decimal, bool) {
+	r, ok := c.Results[pair]
+	if !ok {
+		return Decimal{}, false
+	}
+
+	v, ok := r.Val[date.Format("2006-01-02")]
+	return v, ok
+}
+
+// ConvertHistoricalCompactExact is the compact result of ConvertHistoricalCompactExact API.
+type ConvertHistoricalCompactExact map[string]map[string]Decimal
+
 // Convert returns the currency conversion rate with `[FROM]_[TO]` request.
 func (a *API) Convert(req ConvertRequest) (result *Convert, err error) {
-	return call[Convert](a, true, "convert", func(q url.Values) error {
-		if len(req.Q) == 0 {
-			return errors.New("`Q` require at least one currency conversion")
-		}
+	a, span := a.startSpan("Convert", convertAttributes(req)...)
+	defer func() { a.endSpan(span, err) }()
 
-		q.Add("q", strings.Join(req.Q, ","))
-		return nil
-	})
+	return call[Convert](a, true, "convert", convertQuery(req, false))
 }
 
 // ConvertCompact returns conversion result with compact mode.
 func (a *API) ConvertCompact(req ConvertRequest) (result ConvertCompact, err error) {
-	r, err := call[ConvertCompact](a, true, "convert", func(q url.Values) error {
-		if len(req.Q) == 0 {
-			return errors.New("`Q` require at least one currency conversion")
-		}
+	a, span := a.startSpan("ConvertCompact", convertAttributes(req)...)
+	defer func() { a.endSpan(span, err) }()
 
-		q.Add("compact", "ultra")
-		q.Add("q", strings.Join(req.Q, ","))
-		return nil
-	})
+	r, cacheHit, err := convertCompact[ConvertCompact](a, req)
+	if a.config.Cache != nil {
+		span.SetAttributes(Attribute{AttributeCacheHit, cacheHit})
+	}
 	if err != nil {
 		return ConvertCompact{}, err
 	}
@@ -91,27 +171,141 @@ func (a *API) ConvertCompact(req ConvertRequest) (result ConvertCompact, err err
 
 // ConvertHistorical returns historical currency conversion rate data with target date or date range.
 func (a *API) ConvertHistorical(req ConvertHistoricalRequest) (result *ConvertHistorical, err error) {
-	return call[ConvertHistorical](a, true, "convert", func(q url.Values) error {
+	a, span := a.startSpan("ConvertHistorical", convertHistoricalAttributes(req)...)
+	defer func() { a.endSpan(span, err) }()
+
+	return call[ConvertHistorical](a, true, "convert", convertHistoricalQuery(req, false))
+}
+
+// ConvertHistoricalCompact returns historical data with compact mode.
+func (a *API) ConvertHistoricalCompact(req ConvertHistoricalRequest) (result ConvertHistoricalCompact, err error) {
+	a, span := a.startSpan("ConvertHistoricalCompact", convertHistoricalAttributes(req)...)
+	defer func() { a.endSpan(span, err) }()
+
+	r, err := call[ConvertHistoricalCompact](a, true, "convert", convertHistoricalQuery(req, true))
+	if err != nil {
+		return ConvertHistoricalCompact{}, err
+	}
+
+	return *r, nil
+}
+
+// ConvertExact is the same as Convert, but decodes rates into Decimal without losing precision.
+func (a *API) ConvertExact(req ConvertRequest) (result *ConvertExact, err error) {
+	a, span := a.startSpan("ConvertExact", convertAttributes(req)...)
+	defer func() { a.endSpan(span, err) }()
+
+	return call[ConvertExact](a, true, "convert", convertQuery(req, false))
+}
+
+// ConvertCompactExact is the same as ConvertCompact, but decodes rates into Decimal without losing precision.
+func (a *API) ConvertCompactExact(req ConvertRequest) (result ConvertCompactExact, err error) {
+	a, span := a.startSpan("ConvertCompactExact", convertAttributes(req)...)
+	defer func() { a.endSpan(span, err) }()
+
+	r, cacheHit, err := convertCompact[ConvertCompactExact](a, req)
+	if a.config.Cache != nil {
+		span.SetAttributes(Attribute{AttributeCacheHit, cacheHit})
+	}
+	if err != nil {
+		return ConvertCompactExact{}, err
+	}
+
+	return *r, nil
+}
+
+// ConvertHistoricalExact is the same as ConvertHistorical, but decodes rates into Decimal without losing precision.
+func (a *API) ConvertHistoricalExact(req ConvertHistoricalRequest) (result *ConvertHistoricalExact, err error) {
+	a, span := a.startSpan("ConvertHistoricalExact", convertHistoricalAttributes(req)...)
+	defer func() { a.endSpan(span, err) }()
+
+	return call[ConvertHistoricalExact](a, true, "convert", convertHistoricalQuery(req, false))
+}
+
+// ConvertHistoricalCompactExact is the same as ConvertHistoricalCompact, but decodes rates into Decimal without losing precision.
+func (a *API) ConvertHistoricalCompactExact(req ConvertHistoricalRequest) (result ConvertHistoricalCompactExact, err error) {
+	a, span := a.startSpan("ConvertHistoricalCompactExact", convertHistoricalAttributes(req)...)
+	defer func() { a.endSpan(span, err) }()
+
+	r, err := call[ConvertHistoricalCompactExact](a, true, "convert", convertHistoricalQuery(req, true))
+	if err != nil {
+		return ConvertHistoricalCompactExact{}, err
+	}
+
+	return *r, nil
+}
+
+// ConvertMany returns exact rates of any number of pairs, split into ConvertCompactExact requests within MaxPairsPerRequest.
+func (a *API) ConvertMany(req ConvertRequest) (result ConvertCompactExact, err error) {
+	a, span := a.startSpan("ConvertMany", convertAttributes(req)...)
+	defer func() { a.endSpan(span, err) }()
+
+	if len(req.Q) == 0 {
+		return ConvertCompactExact{}, errors.New("`Q` require at least one currency conversion")
+	}
+
+	result = ConvertCompactExact{}
+	for _, q := range chunk(req.Q, a.maxPairsPerRequest()) {
+		r, err := a.ConvertCompactExact(ConvertRequest{Q: q})
+		if err != nil {
+			return ConvertCompactExact{}, err
+		}
+
+		for pair, rate := range r {
+			result[pair] = rate
+		}
+	}
+
+	return result, nil
+}
+
+// ConvertHistoricalMany returns exact historical rates of any number of pairs and days,
+// split into ConvertHistoricalCompactExact requests within MaxPairsPerRequest and MaxHistoricalDays.
+func (a *API) ConvertHistoricalMany(req ConvertHistoricalRequest) (result ConvertHistoricalCompactExact, err error) {
+	a, span := a.startSpan("ConvertHistoricalMany", convertHistoricalAttributes(req)...)
+	defer func() { a.endSpan(span, err) }()
+
+	if len(req.Q) == 0 {
+		return ConvertHistoricalCompactExact{}, errors.New("`Q` require at least one currency conversion")
+	}
+
+	if req.Date.IsZero() {
+		return ConvertHistoricalCompactExact{}, errors.New("`Date` is required")
+	}
+
+	wanted := map[string]map[string]bool{}
+	for _, pair := range req.Q {
+		for d := req.Date; !d.After(maxTime(req.Date, req.EndDate)); d = d.AddDate(0, 0, 1) {
+			addDate(wanted, pair, formatDate(d))
+		}
+	}
+
+	rates := map[string]map[string]Decimal{}
+	if err := a.fetchHistoricalRates(wanted, rates, map[string]map[string]bool{}, nil); err != nil {
+		return ConvertHistoricalCompactExact{}, err
+	}
+
+	return rates, nil
+}
+
+// convertQuery returns the query handler of Convert APIs.
+func convertQuery(req ConvertRequest, compact bool) func(q url.Values) error {
+	return func(q url.Values) error {
 		if len(req.Q) == 0 {
 			return errors.New("`Q` require at least one currency conversion")
 		}
 
-		if req.Date.IsZero() {
-			return errors.New("`Date` is required")
+		if compact {
+			q.Add("compact", "ultra")
 		}
-
 		q.Add("q", strings.Join(req.Q, ","))
-		q.Add("date", req.Date.Format("2006-01-02"))
-		if !req.EndDate.IsZero() {
-			q.Add("endDate", req.EndDate.Format("2006-01-02"))
-		}
 		return nil
-	})
+	}
 }
 
-// ConvertHistoricalCompact returns historical data with compact mode.
-func (a *API) ConvertHistoricalCompact(req ConvertHistoricalRequest) (result ConvertHistoricalCompact, err error) {
-	r, err := call[ConvertHistoricalCompact](a, true, "convert", func(q url.Values) error {
+// convertHistoricalQuery returns the query handler of ConvertHistorical APIs.
+func convertHistoricalQuery(req ConvertHistoricalRequest, compact bool) func(q url.Values) error {
+	return func(q url.Values) error {
 		if len(req.Q) == 0 {
 			return errors.New("`Q` require at least one currency conversion")
 		}
@@ -120,17 +314,14 @@ func (a *API) ConvertHistoricalCompact(req ConvertHistoricalRequest) (result Con
 			return errors.New("`Date` is required")
 		}
 
-		q.Add("compact", "ultra")
+		if compact {
+			q.Add("compact", "ultra")
+		}
 		q.Add("q", strings.Join(req.Q, ","))
 		q.Add("date", req.Date.Format("2006-01-02"))
 		if !req.EndDate.IsZero() {
 			q.Add("endDate", req.EndDate.Format("2006-01-02"))
 		}
 		return nil
-	})
-	if err != nil {
-		return ConvertHistoricalCompact{}, err
 	}
-
-	return *r, nil
 }
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -521,3 +522,272 @@ func TestAPI_ConvertHistoricalCompact(t *testing.T) {
 		})
 	}
 }
+
+func TestConvert_Rate(t *testing.T) {
+	convert := &Convert{
+		Results: map[string]ConversionResult{
+			"USD_MYR": {ID: "USD_MYR", Val: 4.348493, To: "MYR", Fr: "USD"},
+		},
+	}
+
+	rate, ok := convert.Rate("USD_MYR")
+	assert.True(t, ok)
+	assert.Equal(t, float32(4.348493), rate)
+
+	rate, ok = convert.Rate("MYR_USD")
+	assert.False(t, ok)
+	assert.Equal(t, float32(0), rate)
+}
+
+func TestConvertHistorical_Rate(t *testing.T) {
+	convert := &ConvertHistorical{
+		Results: map[string]HistoricalResult{
+			"USD_MYR": {
+				ID: "USD_MYR",
+				To: "MYR",
+				Fr: "USD",
+				Val: map[string]float32{
+					"2023-02-14": 4.369895,
+				},
+			},
+		},
+	}
+
+	rate, ok := convert.Rate("USD_MYR", time.Date(2023, 2, 14, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, float32(4.369895), rate)
+
+	_, ok = convert.Rate("USD_MYR", time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+
+	_, ok = convert.Rate("MYR_USD", time.Date(2023, 2, 14, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
+
+func TestAPI_ConvertExact(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := url.Values{}
+		q.Add("apiKey", "key")
+		q.Add("q", "BTC_USD")
+		assert.Equal(t, q, r.URL.Query())
+
+		_, _ = w.Write([]byte(`{
+			"query": {
+				"count": 1
+			},
+			"results": {
+				"BTC_USD": {
+					"id": "BTC_USD",
+					"val": 26149.123456789012,
+					"to": "USD",
+					"fr": "BTC"
+				}
+			}
+		}`))
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	convert, err := api.ConvertExact(ConvertRequest{Q: []string{"BTC_USD"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, convert.Query.Count)
+
+	rate, ok := convert.Rate("BTC_USD")
+	assert.True(t, ok)
+	assert.Equal(t, "26149.123456789012", rate.String())
+	assert.Equal(t, "USD", convert.Results["BTC_USD"].To)
+
+	_, err = api.ConvertExact(ConvertRequest{})
+	assert.EqualError(t, err, "`Q` require at least one currency conversion")
+}
+
+func TestAPI_ConvertCompactExact(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := url.Values{}
+		q.Add("apiKey", "key")
+		q.Add("compact", "ultra")
+		q.Add("q", "USD_MYR,MYR_USD")
+		assert.Equal(t, q, r.URL.Query())
+
+		_, _ = w.Write([]byte(`{
+			"USD_MYR": 4.348493,
+			"MYR_USD": 0.229964
+		}`))
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	convert, err := api.ConvertCompactExact(ConvertRequest{Q: []string{"USD_MYR", "MYR_USD"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "4.348493", convert["USD_MYR"].String())
+	assert.Equal(t, "0.229964", convert["MYR_USD"].String())
+
+	convert, err = api.ConvertCompactExact(ConvertRequest{})
+	assert.EqualError(t, err, "`Q` require at least one currency conversion")
+	assert.Equal(t, ConvertCompactExact{}, convert)
+}
+
+func TestAPI_ConvertHistoricalExact(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := url.Values{}
+		q.Add("apiKey", "key")
+		q.Add("q", "USD_MYR")
+		q.Add("date", "2023-02-14")
+		q.Add("endDate", "2023-02-15")
+		assert.Equal(t, q, r.URL.Query())
+
+		_, _ = w.Write([]byte(`{
+			"query": {
+				"count": 1
+			},
+			"date": "2023-02-14",
+			"endDate": "2023-02-15",
+			"results": {
+				"USD_MYR": {
+					"id": "USD_MYR",
+					"fr": "USD",
+					"to": "MYR",
+					"val": {
+						"2023-02-14": 4.369895,
+						"2023-02-15": 4.379895
+					}
+				}
+			}
+		}`))
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	convert, err := api.ConvertHistoricalExact(ConvertHistoricalRequest{
+		Q:       []string{"USD_MYR"},
+		Date:    time.Date(2023, 2, 14, 0, 0, 0, 0, time.UTC),
+		EndDate: time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "2023-02-15", convert.EndDate)
+
+	rate, ok := convert.Rate("USD_MYR", time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "4.379895", rate.String())
+
+	_, ok = convert.Rate("MYR_USD", time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+
+	_, err = api.ConvertHistoricalExact(ConvertHistoricalRequest{Q: []string{"USD_MYR"}})
+	assert.EqualError(t, err, "`Date` is required")
+}
+
+func TestAPI_ConvertHistoricalCompactExact(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := url.Values{}
+		q.Add("apiKey", "key")
+		q.Add("compact", "ultra")
+		q.Add("q", "USD_MYR")
+		q.Add("date", "2023-02-14")
+		assert.Equal(t, q, r.URL.Query())
+
+		_, _ = w.Write([]byte(`{
+			"USD_MYR": {
+				"2023-02-14": 4.348493
+			}
+		}`))
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	convert, err := api.ConvertHistoricalCompactExact(ConvertHistoricalRequest{
+		Q:    []string{"USD_MYR"},
+		Date: time.Date(2023, 2, 14, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "4.348493", convert["USD_MYR"]["2023-02-14"].String())
+
+	convert, err = api.ConvertHistoricalCompactExact(ConvertHistoricalRequest{})
+	assert.EqualError(t, err, "`Q` require at least one currency conversion")
+	assert.Equal(t, ConvertHistoricalCompactExact{}, convert)
+}
+
+func TestAPI_ConvertMany(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		result := map[string]json.RawMessage{}
+		for _, pair := range strings.Split(r.URL.Query().Get("q"), ",") {
+			result[pair] = json.RawMessage("1.5")
+		}
+
+		b, _ := json.Marshal(result)
+		_, _ = w.Write(b)
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	convert, err := api.ConvertMany(ConvertRequest{Q: []string{"USD_MYR", "MYR_USD", "USD_EUR"}})
+	assert.NoError(t, err)
+	assert.Len(t, convert, 3)
+	assert.Equal(t, "1.5", convert["USD_EUR"].String())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	_, err = api.ConvertMany(ConvertRequest{})
+	assert.EqualError(t, err, "`Q` require at least one currency conversion")
+}
+
+func TestAPI_ConvertHistoricalMany(t *testing.T) {
+	var calls int32
+	ts := historicalServer(t, map[string]map[string]string{
+		"USD_MYR": {"2023-02-01": "4.26", "2023-02-10": "4.30"},
+		"MYR_USD": {"2023-02-01": "0.23"},
+		"USD_EUR": {"2023-02-10": "0.93"},
+	}, &calls)
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	convert, err := api.ConvertHistoricalMany(ConvertHistoricalRequest{
+		Q:       []string{"USD_MYR", "MYR_USD", "USD_EUR"},
+		Date:    time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+		EndDate: time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "4.30", convert["USD_MYR"]["2023-02-10"].String())
+	assert.Equal(t, "0.23", convert["MYR_USD"]["2023-02-01"].String())
+	assert.Equal(t, "0.93", convert["USD_EUR"]["2023-02-10"].String())
+	// 2 chunks of pairs, each with 2 windows of at most 8 days.
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+
+	_, err = api.ConvertHistoricalMany(ConvertHistoricalRequest{})
+	assert.EqualError(t, err, "`Q` require at least one currency conversion")
+
+	_, err = api.ConvertHistoricalMany(ConvertHistoricalRequest{Q: []string{"USD_MYR"}})
+	assert.EqualError(t, err, "`Date` is required")
+}
@@ -4,17 +4,35 @@ import "net/url"
 
 // Country is the result of Country API.
 type Country struct {
-	Results map[string]struct {
-		ID             string `json:"id"`
-		Alpha3         string `json:"alpha3"`
-		CurrencyID     string `json:"currencyId"`
-		CurrencyName   string `json:"currencyName"`
-		CurrencySymbol string `json:"currencySymbol"`
-		Name           string `json:"name"`
-	} `json:"results"`
+	Results map[string]CountryInfo `json:"results"`
+}
+
+// CountryInfo is a single country entry of Country.Results.
+type CountryInfo struct {
+	ID             string `json:"id"`
+	Alpha3         string `json:"alpha3"`
+	CurrencyID     string `json:"currencyId"`
+	CurrencyName   string `json:"currencyName"`
+	CurrencySymbol string `json:"currencySymbol"`
+	Name           string `json:"name"`
+}
+
+// Find returns the country with ISO 3166-1 alpha-2 code `id`.
+// The second return value reports whether the country exists in the result.
+func (c *Country) Find(id string) (CountryInfo, bool) {
+	r, ok := c.Results[id]
+	return r, ok
 }
 
 // Countries returns a list of countries.
+// It returns DatasetCountries instead if Config.UseDataset is set.
 func (a *API) Countries() (result *Country, err error) {
+	a, span := a.startSpan("Countries")
+	defer func() { a.endSpan(span, err) }()
+
+	if a.config.UseDataset {
+		return DatasetCountries(), nil
+	}
+
 	return call[Country](a, true, "countries", func(q url.Values) error { return nil })
 }
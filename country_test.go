@@ -82,3 +82,25 @@ func TestAPI_Countries(t *testing.T) {
 		})
 	}
 }
+
+func TestCountry_Find(t *testing.T) {
+	country := &Country{
+		Results: map[string]CountryInfo{
+			"MY": {
+				ID:             "MY",
+				Alpha3:         "MYS",
+				CurrencyID:     "MYR",
+				CurrencyName:   "Malaysian ringgit",
+				CurrencySymbol: "RM",
+				Name:           "Malaysia",
+			},
+		},
+	}
+
+	info, ok := country.Find("MY")
+	assert.True(t, ok)
+	assert.Equal(t, "MYS", info.Alpha3)
+
+	_, ok = country.Find("US")
+	assert.False(t, ok)
+}
@@ -6,14 +6,32 @@ import (
 
 // Currency is the result of Currencies API.
 type Currency struct {
-	Results map[string]struct {
-		ID             string `json:"id"`
-		CurrencyName   string `json:"currencyName"`
-		CurrencySymbol string `json:"currencySymbol"`
-	} `json:"results"`
+	Results map[string]CurrencyInfo `json:"results"`
+}
+
+// CurrencyInfo is a single currency entry of Currency.Results.
+type CurrencyInfo struct {
+	ID             string `json:"id"`
+	CurrencyName   string `json:"currencyName"`
+	CurrencySymbol string `json:"currencySymbol"`
+}
+
+// Find returns the currency with ISO 4217 code `id`.
+// The second return value reports whether the currency exists in the result.
+func (c *Currency) Find(id string) (CurrencyInfo, bool) {
+	r, ok := c.Results[id]
+	return r, ok
 }
 
 // Currencies returns a list of currencies.
+// It returns DatasetCurrencies instead if Config.UseDataset is set.
 func (a *API) Currencies() (result *Currency, err error) {
+	a, span := a.startSpan("Currencies")
+	defer func() { a.endSpan(span, err) }()
+
+	if a.config.UseDataset {
+		return DatasetCurrencies(), nil
+	}
+
 	return call[Currency](a, true, "currencies", func(q url.Values) error { return nil })
 }
@@ -76,3 +76,18 @@ func TestAPI_Currencies(t *testing.T) {
 		})
 	}
 }
+
+func TestCurrency_Find(t *testing.T) {
+	currency := &Currency{
+		Results: map[string]CurrencyInfo{
+			"MYR": {ID: "MYR", CurrencyName: "Malaysian Ringgit", CurrencySymbol: "RM"},
+		},
+	}
+
+	info, ok := currency.Find("MYR")
+	assert.True(t, ok)
+	assert.Equal(t, CurrencyInfo{ID: "MYR", CurrencyName: "Malaysian Ringgit", CurrencySymbol: "RM"}, info)
+
+	_, ok = currency.Find("USD")
+	assert.False(t, ok)
+}
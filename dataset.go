@@ -0,0 +1,99 @@
+package currconv
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sync"
+)
+
+//go:generate go run ./internal/gendataset -currencies dataset/currencies.response.json -countries dataset/countries.response.json -dir dataset
+
+//go:embed dataset/currencies.json
+var datasetCurrenciesJSON []byte
+
+//go:embed dataset/countries.json
+var datasetCountriesJSON []byte
+
+// ISOCurrency is a currency of the embedded dataset, with ISO 4217 fields not returned by Currencies.
+type ISOCurrency struct {
+	CurrencyInfo
+	// MinorUnits is the number of digits after the decimal point.
+	MinorUnits int32 `json:"minorUnits"`
+	// NumericCode is the 3 digits ISO 4217 numeric code, e.g. "458" for MYR.
+	NumericCode string `json:"numericCode"`
+}
+
+// dataset is the decoded embedded dataset.
+type dataset struct {
+	version    string
+	currencies map[string]ISOCurrency
+	countries  map[string]CountryInfo
+}
+
+var (
+	datasetOnce sync.Once
+	embedded    dataset
+)
+
+// loadDataset decodes the embedded dataset once.
+// The dataset is generated and tested, so a decode failure is a bug of the package.
+func loadDataset() dataset {
+	datasetOnce.Do(func() {
+		var currencies struct {
+			Version string                 `json:"version"`
+			Results map[string]ISOCurrency `json:"results"`
+		}
+		if err := json.Unmarshal(datasetCurrenciesJSON, &currencies); err != nil {
+			panic("currconv: invalid embedded currencies: " + err.Error())
+		}
+
+		var countries Country
+		if err := json.Unmarshal(datasetCountriesJSON, &countries); err != nil {
+			panic("currconv: invalid embedded countries: " + err.Error())
+		}
+
+		embedded = dataset{
+			version:    currencies.Version,
+			currencies: currencies.Results,
+			countries:  countries.Results,
+		}
+	})
+
+	return embedded
+}
+
+// DatasetVersion returns the version of the embedded dataset, which is the date it was generated, e.g. "2026-10-19".
+func DatasetVersion() string {
+	return loadDataset().version
+}
+
+// DatasetCurrencies returns the currencies of the embedded dataset, in the same shape as the result of Currencies.
+// It returns a new copy on every call.
+func DatasetCurrencies() *Currency {
+	d := loadDataset()
+	c := &Currency{Results: make(map[string]CurrencyInfo, len(d.currencies))}
+	for id, info := range d.currencies {
+		c.Results[id] = info.CurrencyInfo
+	}
+
+	return c
+}
+
+// DatasetCountries returns the countries of the embedded dataset, in the same shape as the result of Countries.
+// It returns a new copy on every call.
+func DatasetCountries() *Country {
+	d := loadDataset()
+	c := &Country{Results: make(map[string]CountryInfo, len(d.countries))}
+	for id, info := range d.countries {
+		c.Results[id] = info
+	}
+
+	return c
+}
+
+// FindISOCurrency returns the currency with ISO 4217 code `id` from the embedded dataset.
+// The second return value reports whether the currency exists in the dataset.
+func FindISOCurrency(id string) (ISOCurrency, bool) {
+	c, ok := loadDataset().currencies[id]
+	return c, ok
+}
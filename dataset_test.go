@@ -0,0 +1,102 @@
+package currconv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataset(t *testing.T) {
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}$`, DatasetVersion())
+
+	currencies := DatasetCurrencies()
+	countries := DatasetCountries()
+	assert.Greater(t, len(currencies.Results), 150)
+	assert.Greater(t, len(countries.Results), 240)
+
+	numericCodes := map[string]string{}
+	for id, c := range currencies.Results {
+		assert.Equal(t, id, c.ID)
+		assert.NotEmpty(t, c.CurrencyName, id)
+
+		iso, ok := FindISOCurrency(id)
+		assert.True(t, ok, id)
+		assert.Len(t, iso.NumericCode, 3, id)
+		assert.NotContains(t, numericCodes, iso.NumericCode, id)
+		numericCodes[iso.NumericCode] = id
+	}
+
+	for id, c := range countries.Results {
+		assert.Equal(t, id, c.ID)
+		assert.Len(t, c.Alpha3, 3, id)
+
+		currency, ok := currencies.Find(c.CurrencyID)
+		assert.True(t, ok, id)
+		assert.Equal(t, currency.CurrencyName, c.CurrencyName, id)
+	}
+
+	my, ok := countries.Find("MY")
+	assert.True(t, ok)
+	assert.Equal(t, "MYS", my.Alpha3)
+	assert.Equal(t, "MYR", my.CurrencyID)
+}
+
+func TestDataset_Copy(t *testing.T) {
+	c := DatasetCurrencies()
+	delete(c.Results, "MYR")
+
+	_, ok := DatasetCurrencies().Find("MYR")
+	assert.True(t, ok)
+}
+
+func TestFindISOCurrency(t *testing.T) {
+	tests := []struct {
+		id          string
+		exists      bool
+		minorUnits  int32
+		numericCode string
+	}{
+		{"MYR", true, 2, "458"},
+		{"JPY", true, 0, "392"},
+		{"KWD", true, 3, "414"},
+		{"CLF", true, 4, "990"},
+		{"XXX", false, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			c, ok := FindISOCurrency(tt.id)
+			assert.Equal(t, tt.exists, ok)
+			assert.Equal(t, tt.minorUnits, c.MinorUnits)
+			assert.Equal(t, tt.numericCode, c.NumericCode)
+		})
+	}
+}
+
+func TestAPI_UseDataset(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL:    ts.URL,
+		APIKey:     "key",
+		Version:    "v1",
+		UseDataset: true,
+	})
+
+	currencies, err := api.Currencies()
+	assert.NoError(t, err)
+	assert.Equal(t, DatasetCurrencies(), currencies)
+
+	countries, err := api.Countries()
+	assert.NoError(t, err)
+	assert.Equal(t, DatasetCountries(), countries)
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
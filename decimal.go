@@ -0,0 +1,285 @@
+package currconv
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number, used to keep rates exactly as sent by CurrencyConverterAPI.
+// The zero value is 0.
+type Decimal struct {
+	// coef is the unscaled value, nil means 0.
+	coef *big.Int
+	// scale is the number of digits after the decimal point.
+	scale int32
+}
+
+// maxDecimalScale bounds the scale of a parsed Decimal, and the number of zeros added by a positive exponent,
+// so that untrusted input like "1e2147483647" cannot exhaust memory.
+const maxDecimalScale = 1000
+
+// ParseDecimal parses `s` into a Decimal.
+// It accepts plain decimal notation ("-4.348493") and exponent notation ("1.5e-7"),
+// within a scale of -1000 to 1000.
+func ParseDecimal(s string) (Decimal, error) {
+	invalid := fmt.Errorf("invalid decimal %q", s)
+
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, invalid
+		}
+		mantissa, exponent = s[:i], e
+	}
+
+	sign := ""
+	if mantissa != "" && (mantissa[0] == '-' || mantissa[0] == '+') {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	if intPart == "" && fracPart == "" {
+		return Decimal{}, invalid
+	}
+
+	digits := intPart + fracPart
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return Decimal{}, invalid
+		}
+	}
+
+	coef, ok := new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return Decimal{}, invalid
+	}
+
+	scale := int64(len(fracPart)) - exponent
+	if scale < -maxDecimalScale || scale > maxDecimalScale {
+		return Decimal{}, fmt.Errorf("decimal %q out of range", s)
+	}
+
+	if scale < 0 {
+		coef.Mul(coef, pow10(-scale))
+		scale = 0
+	}
+
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if `s` cannot be parsed.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// NewDecimalFromInt returns `i` as a Decimal.
+func NewDecimalFromInt(i int64) Decimal {
+	return Decimal{coef: big.NewInt(i)}
+}
+
+// NewDecimalFromFloat returns the shortest Decimal that represents `f`.
+// It returns an error if `f` is NaN or infinite.
+func NewDecimalFromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("invalid decimal %v", f)
+	}
+
+	return ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// String returns the decimal notation of d, keeping its scale.
+func (d Decimal) String() string {
+	if d.coef == nil {
+		if d.scale > 0 {
+			return "0." + strings.Repeat("0", int(d.scale))
+		}
+		return "0"
+	}
+
+	s := new(big.Int).Abs(d.coef).String()
+	if d.scale > 0 {
+		if len(s) <= int(d.scale) {
+			s = strings.Repeat("0", int(d.scale)-len(s)+1) + s
+		}
+		s = s[:len(s)-int(d.scale)] + "." + s[len(s)-int(d.scale):]
+	}
+
+	if d.coef.Sign() < 0 {
+		return "-" + s
+	}
+
+	return s
+}
+
+// Float64 returns the nearest float64 value of d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	if d.coef == nil {
+		return 0
+	}
+
+	return d.coef.Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp compares d and e and returns -1, 0 or +1.
+func (d Decimal) Cmp(e Decimal) int {
+	a, b := rescaled(d, e)
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and e represent the same number, regardless of scale.
+func (d Decimal) Equal(e Decimal) bool {
+	return d.Cmp(e) == 0
+}
+
+// Add returns d + e.
+func (d Decimal) Add(e Decimal) Decimal {
+	a, b := rescaled(d, e)
+	return Decimal{coef: new(big.Int).Add(a, b), scale: maxScale(d, e)}
+}
+
+// Sub returns d - e.
+func (d Decimal) Sub(e Decimal) Decimal {
+	a, b := rescaled(d, e)
+	return Decimal{coef: new(big.Int).Sub(a, b), scale: maxScale(d, e)}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.unscaled()), scale: d.scale}
+}
+
+// Mul returns d * e.
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.unscaled(), e.unscaled()), scale: d.scale + e.scale}
+}
+
+// Div returns d / e rounded half away from zero to `places` digits after the decimal point.
+// Div panics if e is 0.
+func (d Decimal) Div(e Decimal, places int32) Decimal {
+	if e.IsZero() {
+		panic("currconv: division by zero")
+	}
+
+	if places < 0 {
+		return d.Div(e, 0).Round(places)
+	}
+
+	// d / e * 10^places = d.coef * 10^(e.scale + places - d.scale) / e.coef
+	num, den := new(big.Int).Set(d.unscaled()), new(big.Int).Set(e.unscaled())
+	if exp := int64(e.scale) + int64(places) - int64(d.scale); exp >= 0 {
+		num.Mul(num, pow10(exp))
+	} else {
+		den.Mul(den, pow10(-exp))
+	}
+
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Abs(r).Lsh(r, 1).Cmp(new(big.Int).Abs(den)) >= 0 {
+		q.Add(q, big.NewInt(int64(num.Sign()*den.Sign())))
+	}
+
+	return Decimal{coef: q, scale: places}
+}
+
+// Round rounds d half away from zero to `places` digits after the decimal point.
+// The result always has `places` digits, so 1.5 rounded to 2 places is 1.50.
+// A negative `places` rounds to tens, hundreds and so on.
+func (d Decimal) Round(places int32) Decimal {
+	if places < 0 {
+		r := Decimal{coef: d.coef, scale: d.scale - places}.Round(0)
+		return Decimal{coef: new(big.Int).Mul(r.unscaled(), pow10(int64(-places)))}
+	}
+
+	if d.scale <= places {
+		return Decimal{coef: new(big.Int).Mul(d.unscaled(), pow10(int64(places-d.scale))), scale: places}
+	}
+
+	unit := pow10(int64(d.scale - places))
+	q, r := new(big.Int).QuoRem(d.unscaled(), unit, new(big.Int))
+	if r.Abs(r).Lsh(r, 1).Cmp(unit) >= 0 {
+		q.Add(q, big.NewInt(int64(d.Sign())))
+	}
+
+	return Decimal{coef: q, scale: places}
+}
+
+// MarshalJSON implements json.Marshaler, d is written as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepts both JSON number and string.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	s := string(b)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+
+	*d = v
+	return nil
+}
+
+// unscaled returns the unscaled value of d, never nil.
+func (d Decimal) unscaled() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+
+	return d.coef
+}
+
+// rescaled returns the unscaled values of d and e with a common scale.
+func rescaled(d, e Decimal) (*big.Int, *big.Int) {
+	a, b := d.unscaled(), e.unscaled()
+	switch {
+	case d.scale < e.scale:
+		a = new(big.Int).Mul(a, pow10(int64(e.scale-d.scale)))
+	case d.scale > e.scale:
+		b = new(big.Int).Mul(b, pow10(int64(d.scale-e.scale)))
+	}
+
+	return a, b
+}
+
+// maxScale returns the larger scale of d and e.
+func maxScale(d, e Decimal) int32 {
+	if d.scale > e.scale {
+		return d.scale
+	}
+
+	return e.scale
+}
+
+// pow10 returns 10^n.
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
@@ -0,0 +1,187 @@
+package currconv
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		float    float64
+		error    string
+	}{
+		{"Integer", "42", "42", 42, ""},
+		{"Fraction", "4.348493", "4.348493", 4.348493, ""},
+		{"Negative fraction", "-0.229964", "-0.229964", -0.229964, ""},
+		{"Keeps trailing zeros", "1.50", "1.50", 1.5, ""},
+		{"Leading dot", ".5", "0.5", 0.5, ""},
+		{"Positive sign", "+3", "3", 3, ""},
+		{"Negative exponent", "1.5e-7", "0.00000015", 0.00000015, ""},
+		{"Positive exponent", "1.5E3", "1500", 1500, ""},
+		{"High precision", "26149.123456789012345678", "26149.123456789012345678", 26149.123456789012345678, ""},
+		{"Empty", "", "", 0, "invalid decimal \"\""},
+		{"Only dot", ".", "", 0, "invalid decimal \".\""},
+		{"Letters", "1.2a", "", 0, "invalid decimal \"1.2a\""},
+		{"Bad exponent", "1e", "", 0, "invalid decimal \"1e\""},
+		{"Exponent too large", "1e2147483647", "", 0, "decimal \"1e2147483647\" out of range"},
+		{"Exponent too small", "0.1e-2147483647", "", 0, "decimal \"0.1e-2147483647\" out of range"},
+		{"Fraction too long", "0." + strings.Repeat("1", 1001), "", 0, "decimal \"0." + strings.Repeat("1", 1001) + "\" out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseDecimal(tt.input)
+			if tt.error != "" {
+				assert.EqualError(t, err, tt.error)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, d.String())
+			assert.Equal(t, tt.float, d.Float64())
+		})
+	}
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"1.5", "1.50", 0},
+		{"1.5", "1.49", 1},
+		{"-1", "0", -1},
+		{"0.000001", "0", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MustParseDecimal(tt.a).Cmp(MustParseDecimal(tt.b)))
+		})
+	}
+
+	assert.True(t, Decimal{}.IsZero())
+	assert.Equal(t, "0", Decimal{}.String())
+	assert.True(t, MustParseDecimal("0.00").Equal(Decimal{}))
+}
+
+func TestNewDecimalFromFloat(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected string
+		errorMsg string
+	}{
+		{4.348493, "4.348493", ""},
+		{-0.1, "-0.1", ""},
+		{math.NaN(), "", "invalid decimal NaN"},
+		{math.Inf(1), "", "invalid decimal +Inf"},
+		{math.Inf(-1), "", "invalid decimal -Inf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.input), func(t *testing.T) {
+			d, err := NewDecimalFromFloat(tt.input)
+			if tt.errorMsg != "" {
+				assert.EqualError(t, err, tt.errorMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, d.String())
+		})
+	}
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	var v struct {
+		Number Decimal  `json:"number"`
+		String Decimal  `json:"string"`
+		Null   *Decimal `json:"null"`
+	}
+
+	err := json.Unmarshal([]byte(`{"number": 4.3484930, "string": "0.229964", "null": null}`), &v)
+	assert.NoError(t, err)
+	assert.Equal(t, "4.3484930", v.Number.String())
+	assert.Equal(t, "0.229964", v.String.String())
+	assert.Nil(t, v.Null)
+
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"number": 4.3484930, "string": 0.229964, "null": null}`, string(b))
+
+	err = json.Unmarshal([]byte(`{"number": "abc"}`), &v)
+	assert.EqualError(t, err, "invalid decimal \"abc\"")
+}
+
+func TestDecimal_Mul(t *testing.T) {
+	assert.Equal(t, "45.6591765", MustParseDecimal("10.5").Mul(MustParseDecimal("4.348493")).String())
+	assert.Equal(t, "0", Decimal{}.Mul(MustParseDecimal("4")).String())
+}
+
+func TestDecimal_Round(t *testing.T) {
+	tests := []struct {
+		input    string
+		places   int32
+		expected string
+	}{
+		{"1.005", 2, "1.01"},
+		{"1.004", 2, "1.00"},
+		{"-1.005", 2, "-1.01"},
+		{"1.5", 2, "1.50"},
+		{"2.5", 0, "3"},
+		{"1234.5", -2, "1200"},
+		{"1250", -2, "1300"},
+		{"0", 2, "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MustParseDecimal(tt.input).Round(tt.places).String())
+		})
+	}
+}
+
+func TestDecimal_AddSub(t *testing.T) {
+	assert.Equal(t, "5.848493", MustParseDecimal("1.5").Add(MustParseDecimal("4.348493")).String())
+	assert.Equal(t, "-2.848493", MustParseDecimal("1.5").Sub(MustParseDecimal("4.348493")).String())
+	assert.Equal(t, "1.5", Decimal{}.Add(MustParseDecimal("1.5")).String())
+	assert.Equal(t, "42", NewDecimalFromInt(42).String())
+	assert.Equal(t, "-1.50", MustParseDecimal("1.50").Neg().String())
+	assert.Equal(t, "0", Decimal{}.Neg().String())
+}
+
+func TestDecimal_Div(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		places   int32
+		expected string
+	}{
+		{"1", "3", 6, "0.333333"},
+		{"2", "3", 6, "0.666667"},
+		{"-2", "3", 2, "-0.67"},
+		{"2", "-3", 2, "-0.67"},
+		{"28.17", "31", 6, "0.908710"},
+		{"1", "0.229964", 4, "4.3485"},
+		{"12345", "1", -2, "12300"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MustParseDecimal(tt.a).Div(MustParseDecimal(tt.b), tt.places).String())
+		})
+	}
+
+	assert.Panics(t, func() {
+		MustParseDecimal("1").Div(Decimal{}, 2)
+	})
+}
@@ -0,0 +1,215 @@
+package currconv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// errFlightPanicked is the error shared with the waiters of a call which panicked.
+var errFlightPanicked = errors.New("shared request panicked")
+
+// flightGroup deduplicates concurrent identical requests, so that they share one HTTP round trip.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+	pairs map[string]*flightCall
+}
+
+// flightCall is an in-flight request, or an in-flight rate of a pair.
+type flightCall struct {
+	done chan struct{}
+	body []byte
+	err  error
+	// pairs are all pairs of the request fetching an in-flight pair.
+	pairs []string
+	// dups is the number of calls sharing an in-flight request.
+	dups int
+}
+
+// newFlightGroup create and return a flightGroup.
+func newFlightGroup() *flightGroup {
+	return &flightGroup{
+		calls: map[string]*flightCall{},
+		pairs: map[string]*flightCall{},
+	}
+}
+
+// do runs `fn` for `key` if no call of `key` is in flight, otherwise waits for the call in flight and shares its result,
+// or returns the error of `ctx` if it is done first.
+// `leader` reports whether `fn` was run by this call.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() ([]byte, error)) (body []byte, leader bool, err error) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+
+		select {
+		case <-c.done:
+			return c.body, false, c.err
+		case <-ctx.Done():
+			g.mu.Lock()
+			c.dups--
+			g.mu.Unlock()
+			return nil, false, ctx.Err()
+		}
+	}
+
+	c := &flightCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+
+	c.err = errFlightPanicked
+	c.body, c.err = fn()
+
+	return c.body, true, c.err
+}
+
+// claimPairs splits `pairs` into the pairs this call must fetch, registered as in flight,
+// and the in-flight pairs to wait for.
+func (g *flightGroup) claimPairs(pairs []string) (own []string, waits map[string]*flightCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	waits = map[string]*flightCall{}
+	for _, pair := range pairs {
+		if _, ok := waits[pair]; ok {
+			continue
+		}
+
+		if c, ok := g.pairs[pair]; ok {
+			waits[pair] = c
+			continue
+		}
+
+		c := &flightCall{done: make(chan struct{})}
+		g.pairs[pair] = c
+		waits[pair] = c
+		own = append(own, pair)
+	}
+
+	for _, pair := range own {
+		waits[pair].pairs = own
+	}
+
+	return own, waits
+}
+
+// resolvePairs completes the in-flight `pairs` with their rates in `rates`, or `err`.
+// A pair without a rate completes with a nil body.
+func (g *flightGroup) resolvePairs(pairs []string, rates map[string]json.RawMessage, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, pair := range pairs {
+		c := g.pairs[pair]
+		delete(g.pairs, pair)
+
+		c.body, c.err = rates[pair], err
+		close(c.done)
+	}
+}
+
+// compactRates is the compact result of the Convert API with raw rates, shared between calls requesting the same pairs.
+type compactRates map[string]json.RawMessage
+
+// fetchRates fetches the compact rates of `pairs`.
+// The rate of each pair is fetched once for concurrent calls requesting it, even if they request different pairs.
+func (a *API) fetchRates(pairs []string) (compactRates, error) {
+	if a.flight == nil {
+		r, err := call[compactRates](a, true, "convert", convertQuery(ConvertRequest{Q: pairs}, true))
+		if err != nil {
+			return nil, err
+		}
+
+		return *r, nil
+	}
+
+	own, waits := a.flight.claimPairs(pairs)
+	if len(own) > 0 {
+		a.fetchOwnRates(own)
+	}
+
+	wanted := map[string]bool{}
+	for _, pair := range pairs {
+		wanted[pair] = true
+	}
+
+	ctx := a.context()
+	rates := compactRates{}
+	var retry []string
+	for pair, c := range waits {
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if c.err != nil {
+			if shouldRefetch(ctx, c, wanted) {
+				retry = append(retry, pair)
+				continue
+			}
+
+			return nil, c.err
+		}
+
+		if c.body != nil {
+			rates[pair] = c.body
+		}
+	}
+
+	if len(retry) > 0 {
+		r, err := call[compactRates](a, true, "convert", convertQuery(ConvertRequest{Q: retry}, true))
+		if err != nil {
+			return nil, err
+		}
+
+		for pair, rate := range *r {
+			rates[pair] = rate
+		}
+	}
+
+	return rates, nil
+}
+
+// fetchOwnRates fetches the in-flight `pairs` claimed by this call, and completes them even if the fetch panics.
+func (a *API) fetchOwnRates(pairs []string) {
+	var rates compactRates
+	err := errFlightPanicked
+	defer func() { a.flight.resolvePairs(pairs, rates, err) }()
+
+	r, err := call[compactRates](a, true, "convert", convertQuery(ConvertRequest{Q: pairs}, true))
+	if r != nil {
+		rates = *r
+	}
+}
+
+// shouldRefetch reports whether a pair whose shared fetch `c` failed should be fetched again by a call with `ctx` wanting
+// `wanted` pairs, because the failure may not apply to it: the fetch was canceled by another caller, or requested other pairs
+// which may have been rejected.
+func shouldRefetch(ctx context.Context, c *flightCall, wanted map[string]bool) bool {
+	if ctx.Err() != nil || errors.Is(c.err, ErrCircuitOpen) {
+		return false
+	}
+
+	if errors.Is(c.err, context.Canceled) {
+		return true
+	}
+
+	for _, pair := range c.pairs {
+		if !wanted[pair] {
+			return true
+		}
+	}
+
+	return false
+}
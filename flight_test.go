@@ -0,0 +1,303 @@
+package currconv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dups returns the number of calls sharing the in-flight request of `key`.
+func (g *flightGroup) dups(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if c, ok := g.calls[key]; ok {
+		return c.dups
+	}
+
+	return -1
+}
+
+func TestFlight_IdenticalCalls(t *testing.T) {
+	received := make(chan string, 10)
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.URL.RawQuery
+		<-release
+		_, _ = w.Write([]byte(`{"results": {"MYR": {"currencyName": "Malaysian Ringgit", "currencySymbol": "RM", "id": "MYR"}}}`))
+	}))
+	defer ts.Close()
+
+	var requests int
+	var mu sync.Mutex
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "secret",
+		Version: "v1",
+		Hooks: Hooks{OnRequest: func(RequestInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			requests++
+		}},
+	})
+
+	const n = 10
+	results := make([]*Currency, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r, err := api.Currencies()
+			assert.NoError(t, err)
+			results[i] = r
+		}(i)
+	}
+
+	<-received
+	key := ts.URL + "/api/v1/currencies?apiKey=secret"
+	assert.Eventually(t, func() bool { return api.flight.dups(key) == n-1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1, requests)
+	assert.Len(t, received, 0)
+	for _, r := range results {
+		assert.Equal(t, "Malaysian Ringgit", r.Results["MYR"].CurrencyName)
+	}
+
+	results[0].Results["MYR"] = CurrencyInfo{}
+	assert.Equal(t, "Malaysian Ringgit", results[1].Results["MYR"].CurrencyName, "callers must not share decoded results")
+
+	_, err := api.Currencies()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests, "a finished request is not shared")
+}
+
+func TestFlight_SharedPairs(t *testing.T) {
+	received := make(chan string, 10)
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		received <- q
+		if strings.Contains(q, "EUR_MYR") {
+			<-release
+		}
+
+		rates := map[string]string{"USD_MYR": "4.348493", "EUR_MYR": "4.6809", "SGD_MYR": "3.2786"}
+		var parts []string
+		for _, pair := range strings.Split(q, ",") {
+			parts = append(parts, `"`+pair+`": `+rates[pair])
+		}
+		_, _ = w.Write([]byte("{" + strings.Join(parts, ",") + "}"))
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "secret",
+		Version: "v1",
+	})
+
+	var a ConvertCompact
+	var b ConvertCompactExact
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var err error
+		a, err = api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR", "EUR_MYR"}})
+		assert.NoError(t, err)
+	}()
+	assert.Equal(t, "USD_MYR,EUR_MYR", <-received)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var err error
+		b, err = api.ConvertCompactExact(ConvertRequest{Q: []string{"USD_MYR", "SGD_MYR"}})
+		assert.NoError(t, err)
+	}()
+	assert.Equal(t, "SGD_MYR", <-received, "USD_MYR is already in flight")
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, ConvertCompact{"USD_MYR": 4.348493, "EUR_MYR": 4.6809}, a)
+	assert.Equal(t, "4.348493", b["USD_MYR"].String())
+	assert.Equal(t, "3.2786", b["SGD_MYR"].String())
+	assert.Len(t, received, 0)
+}
+
+func TestFlight_SharedPairsError(t *testing.T) {
+	received := make(chan string, 10)
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		received <- q
+		if strings.Contains(q, "XXX_MYR") {
+			<-release
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status": 400, "error": "Invalid currency XXX"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"USD_MYR": 4.348493}`))
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "secret",
+		Version: "v1",
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR", "XXX_MYR"}})
+		assert.EqualError(t, err, "Invalid currency XXX")
+	}()
+	assert.Equal(t, "USD_MYR,XXX_MYR", <-received)
+
+	var r ConvertCompact
+	var err error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r, err = api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+	}()
+
+	close(release)
+	wg.Wait()
+	<-done
+
+	assert.NoError(t, err, "a pair rejected along with other pairs is fetched again")
+	assert.Equal(t, ConvertCompact{"USD_MYR": 4.348493}, r)
+	assert.Equal(t, "USD_MYR", <-received)
+}
+
+func TestFlight_CanceledLeader(t *testing.T) {
+	received := make(chan struct{}, 10)
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = w.Write([]byte(`{"timestamp": "2023-02-15T02:05:49.988Z", "usage": 1}`))
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{BaseURL: ts.URL, APIKey: "secret"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan error)
+	go func() {
+		_, err := api.WithContext(ctx).Usage()
+		leaderDone <- err
+	}()
+	<-received
+
+	followerDone := make(chan error)
+	go func() {
+		_, err := api.Usage()
+		followerDone <- err
+	}()
+	key := ts.URL + "/others/usage?apiKey=secret"
+	assert.Eventually(t, func() bool { return api.flight.dups(key) == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-leaderDone, context.Canceled)
+
+	<-received
+	close(release)
+	assert.NoError(t, <-followerDone, "a follower is not canceled by its leader")
+}
+
+func TestFlight_WaiterDeadline(t *testing.T) {
+	received := make(chan struct{}, 10)
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+		_, _ = w.Write([]byte(`{"timestamp": "2023-02-15T02:05:49.988Z", "usage": 1}`))
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	api := NewAPI(Config{BaseURL: ts.URL, APIKey: "secret"})
+
+	go func() {
+		_, _ = api.Usage()
+	}()
+	<-received
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := api.WithContext(ctx).Usage()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second, "a waiter returns at its own deadline, while the leader is blocked")
+
+	key := ts.URL + "/others/usage?apiKey=secret"
+	assert.Equal(t, 0, api.flight.dups(key))
+}
+
+func TestFlight_PanickedLeader(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"USD_MYR": 4.348493, "EUR_MYR": 4.748493}`))
+	}))
+	defer ts.Close()
+
+	waiting := make(chan struct{})
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "secret",
+		Version: "v1",
+		Hooks: Hooks{OnRequest: func(info RequestInfo) {
+			if strings.Contains(info.URL, "EUR_MYR") {
+				// The waiter fetches EUR_MYR after it claimed to wait for USD_MYR.
+				close(waiting)
+				return
+			}
+
+			<-waiting
+			panic("hook")
+		}},
+	})
+
+	leaderDone := make(chan interface{})
+	go func() {
+		defer func() { leaderDone <- recover() }()
+		_, _ = api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+	}()
+	assert.Eventually(t, func() bool {
+		api.flight.mu.Lock()
+		defer api.flight.mu.Unlock()
+		return api.flight.pairs["USD_MYR"] != nil
+	}, time.Second, time.Millisecond)
+
+	waiterDone := make(chan error)
+	go func() {
+		_, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR", "EUR_MYR"}})
+		waiterDone <- err
+	}()
+
+	assert.Equal(t, "hook", <-leaderDone)
+	select {
+	case err := <-waiterDone:
+		assert.ErrorIs(t, err, errFlightPanicked)
+	case <-time.After(time.Second):
+		t.Fatal("a waiter of a pair is blocked after its leader panicked")
+	}
+}
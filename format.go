@@ -0,0 +1,207 @@
+package currconv
+
+import (
+	"strings"
+)
+
+// Locale is the number and currency format of a locale.
+type Locale struct {
+	// Decimal separates the integer and the fraction.
+	Decimal string
+	// Group separates groups of digits of the integer.
+	Group string
+	// SecondaryGrouping groups digits by 2 after the first group of 3, e.g. 12,34,567 in en-IN.
+	SecondaryGrouping bool
+	// SymbolAfter places the symbol after the number.
+	SymbolAfter bool
+	// Space separates the symbol and the number.
+	Space bool
+}
+
+// locales are the formats of common locales, keyed by BCP 47 tag.
+var locales = map[string]Locale{
+	"en-US": {Decimal: ".", Group: ","},
+	"en-GB": {Decimal: ".", Group: ","},
+	"en-AU": {Decimal: ".", Group: ","},
+	"en-CA": {Decimal: ".", Group: ","},
+	"en-IN": {Decimal: ".", Group: ",", SecondaryGrouping: true},
+	"en-MY": {Decimal: ".", Group: ","},
+	"en-SG": {Decimal: ".", Group: ","},
+	"ms-MY": {Decimal: ".", Group: ","},
+	"zh-CN": {Decimal: ".", Group: ","},
+	"zh-TW": {Decimal: ".", Group: ","},
+	"zh-HK": {Decimal: ".", Group: ","},
+	"ja-JP": {Decimal: ".", Group: ","},
+	"ko-KR": {Decimal: ".", Group: ","},
+	"th-TH": {Decimal: ".", Group: ","},
+	"hi-IN": {Decimal: ".", Group: ",", SecondaryGrouping: true},
+	"id-ID": {Decimal: ",", Group: "."},
+	"de-DE": {Decimal: ",", Group: ".", SymbolAfter: true, Space: true},
+	"de-AT": {Decimal: ",", Group: ".", Space: true},
+	"de-CH": {Decimal: ".", Group: "’", Space: true},
+	"fr-FR": {Decimal: ",", Group: " ", SymbolAfter: true, Space: true},
+	"fr-CA": {Decimal: ",", Group: " ", SymbolAfter: true, Space: true},
+	"es-ES": {Decimal: ",", Group: ".", SymbolAfter: true, Space: true},
+	"es-MX": {Decimal: ".", Group: ","},
+	"it-IT": {Decimal: ",", Group: ".", SymbolAfter: true, Space: true},
+	"nl-NL": {Decimal: ",", Group: ".", Space: true},
+	"pt-BR": {Decimal: ",", Group: ".", Space: true},
+	"pt-PT": {Decimal: ",", Group: " ", SymbolAfter: true, Space: true},
+	"pl-PL": {Decimal: ",", Group: " ", SymbolAfter: true, Space: true},
+	"ru-RU": {Decimal: ",", Group: " ", SymbolAfter: true, Space: true},
+	"sv-SE": {Decimal: ",", Group: " ", SymbolAfter: true, Space: true},
+	"tr-TR": {Decimal: ",", Group: "."},
+	"vi-VN": {Decimal: ",", Group: ".", SymbolAfter: true, Space: true},
+}
+
+// defaultLocale is used when a locale is unknown.
+const defaultLocale = "en-US"
+
+// languageLocales are the locales used when only the language of a tag is known.
+var languageLocales = map[string]string{
+	"de": "de-DE", "en": "en-US", "es": "es-ES", "fr": "fr-FR", "hi": "hi-IN", "id": "id-ID", "it": "it-IT", "ja": "ja-JP",
+	"ko": "ko-KR", "ms": "ms-MY", "nl": "nl-NL", "pl": "pl-PL", "pt": "pt-BR", "ru": "ru-RU", "sv": "sv-SE", "th": "th-TH",
+	"tr": "tr-TR", "vi": "vi-VN", "zh": "zh-CN",
+}
+
+// symbols are the currency symbols preferred over those of the embedded dataset, mostly to tell apart currencies
+// sharing a symbol, e.g. "A$" rather than "$" for AUD.
+var symbols = map[string]string{
+	"AED": "د.إ", "ARS": "$", "AUD": "A$", "BDT": "৳", "BRL": "R$", "CAD": "CA$", "CHF": "CHF", "CNY": "¥",
+	"CZK": "Kč", "DKK": "kr", "EGP": "E£", "EUR": "€", "GBP": "£", "HKD": "HK$", "HUF": "Ft", "IDR": "Rp",
+	"ILS": "₪", "INR": "₹", "JPY": "¥", "KRW": "₩", "MXN": "MX$", "MYR": "RM", "NGN": "₦", "NOK": "kr",
+	"NZD": "NZ$", "PHP": "₱", "PKR": "₨", "PLN": "zł", "RUB": "₽", "SAR": "﷼", "SEK": "kr", "SGD": "S$",
+	"THB": "฿", "TRY": "₺", "TWD": "NT$", "UAH": "₴", "USD": "$", "VND": "₫", "ZAR": "R",
+}
+
+// Formatter formats Money by locale.
+type Formatter struct {
+	// Symbols overrides the embedded symbols, keyed by ISO 4217 code.
+	Symbols map[string]string
+	// UseCode formats with the ISO 4217 code instead of the symbol, e.g. "MYR 1,234.50".
+	UseCode bool
+}
+
+// NewFormatter create and return a Formatter with symbols from the result of Currencies.
+// Currencies without symbol keep their embedded symbol.
+func NewFormatter(currencies *Currency) *Formatter {
+	f := &Formatter{Symbols: map[string]string{}}
+	if currencies == nil {
+		return f
+	}
+
+	for id, c := range currencies.Results {
+		if c.CurrencySymbol != "" {
+			f.Symbols[id] = c.CurrencySymbol
+		}
+	}
+
+	return f
+}
+
+// Format formats `m` by `locale` with the embedded symbols, e.g. "RM1,234.50", "1.234,50 €" or "¥1,235".
+// `m` is rounded to the minor units of its currency.
+// An unknown locale falls back to another locale of the same language, and then to "en-US".
+func Format(m Money, locale string) string {
+	return (&Formatter{}).Format(m, locale)
+}
+
+// FormatCode is the same as Format, but formats with the ISO 4217 code, e.g. "MYR 1,234.50" or "1.234,50 EUR".
+func FormatCode(m Money, locale string) string {
+	return (&Formatter{UseCode: true}).Format(m, locale)
+}
+
+// Format formats `m` by `locale`, see the package level Format.
+func (f *Formatter) Format(m Money, locale string) string {
+	l := FindLocale(locale)
+	number := formatNumber(m.Round().Amount, l)
+
+	negative := strings.HasPrefix(number, "-")
+	number = strings.TrimPrefix(number, "-")
+
+	symbol, space := f.symbol(m.Currency), l.Space
+	if f.UseCode || symbol == m.Currency {
+		symbol, space = m.Currency, true
+	}
+
+	sep := ""
+	if space {
+		sep = " "
+	}
+
+	s := symbol + sep + number
+	if l.SymbolAfter {
+		s = number + sep + symbol
+	}
+
+	if negative {
+		return "-" + s
+	}
+
+	return s
+}
+
+// symbol returns the symbol of `currency`, or the code if there is no symbol.
+func (f *Formatter) symbol(currency string) string {
+	if s, ok := f.Symbols[currency]; ok {
+		return s
+	}
+
+	if s, ok := symbols[currency]; ok {
+		return s
+	}
+
+	if c, ok := FindISOCurrency(currency); ok && c.CurrencySymbol != "" {
+		return c.CurrencySymbol
+	}
+
+	return currency
+}
+
+// FindLocale returns the format of `tag`, e.g. "de-DE" or "de_DE".
+// An unknown tag falls back to another locale of the same language, and then to "en-US".
+func FindLocale(tag string) Locale {
+	tag = strings.ReplaceAll(tag, "_", "-")
+	for t, l := range locales {
+		if strings.EqualFold(t, tag) {
+			return l
+		}
+	}
+
+	lang, _, _ := strings.Cut(tag, "-")
+	if t, ok := languageLocales[strings.ToLower(lang)]; ok {
+		return locales[t]
+	}
+
+	return locales[defaultLocale]
+}
+
+// formatNumber formats `d` with separators of `l`.
+func formatNumber(d Decimal, l Locale) string {
+	s := d.String()
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+
+	var groups []string
+	size := 3
+	for len(intPart) > size {
+		groups = append([]string{intPart[len(intPart)-size:]}, groups...)
+		intPart = intPart[:len(intPart)-size]
+		if l.SecondaryGrouping {
+			size = 2
+		}
+	}
+	groups = append([]string{intPart}, groups...)
+
+	s = sign + strings.Join(groups, l.Group)
+	if hasFrac {
+		s += l.Decimal + fracPart
+	}
+
+	return s
+}
@@ -0,0 +1,55 @@
+package currconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		locale   string
+		expected string
+		code     string
+	}{
+		{"Malaysia", NewMoney(MustParseDecimal("1234.5"), "MYR"), "ms-MY", "RM1,234.50", "MYR 1,234.50"},
+		{"Germany", NewMoney(MustParseDecimal("1234.5"), "EUR"), "de-DE", "1.234,50 €", "1.234,50 EUR"},
+		{"Japan", NewMoney(MustParseDecimal("1234.5"), "JPY"), "ja-JP", "¥1,235", "JPY 1,235"},
+		{"France", NewMoney(MustParseDecimal("1234567.891"), "EUR"), "fr-FR", "1 234 567,89 €", "1 234 567,89 EUR"},
+		{"Netherlands", NewMoney(MustParseDecimal("1234.5"), "EUR"), "nl-NL", "€ 1.234,50", "EUR 1.234,50"},
+		{"Switzerland", NewMoney(MustParseDecimal("1234.5"), "CHF"), "de-CH", "CHF 1’234.50", "CHF 1’234.50"},
+		{"India", NewMoney(MustParseDecimal("1234567"), "INR"), "en-IN", "₹12,34,567.00", "INR 12,34,567.00"},
+		{"Kuwait", NewMoney(MustParseDecimal("1.5"), "KWD"), "en-US", "KD1.500", "KWD 1.500"},
+		{"Negative", NewMoney(MustParseDecimal("-1234.5"), "USD"), "en-US", "-$1,234.50", "-USD 1,234.50"},
+		{"Negative after", NewMoney(MustParseDecimal("-0.5"), "EUR"), "de-DE", "-0,50 €", "-0,50 EUR"},
+		{"Small", NewMoney(MustParseDecimal("12"), "USD"), "en-US", "$12.00", "USD 12.00"},
+		{"Underscore locale", NewMoney(MustParseDecimal("1234.5"), "EUR"), "de_de", "1.234,50 €", "1.234,50 EUR"},
+		{"Language only", NewMoney(MustParseDecimal("1234.5"), "EUR"), "de", "1.234,50 €", "1.234,50 EUR"},
+		{"Unknown locale", NewMoney(MustParseDecimal("1234.5"), "USD"), "xx-YY", "$1,234.50", "USD 1,234.50"},
+		{"Unknown currency", NewMoney(MustParseDecimal("1234.5"), "XYZ"), "en-US", "XYZ 1,234.50", "XYZ 1,234.50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Format(tt.money, tt.locale))
+			assert.Equal(t, tt.code, FormatCode(tt.money, tt.locale))
+		})
+	}
+}
+
+func TestNewFormatter(t *testing.T) {
+	f := NewFormatter(&Currency{
+		Results: map[string]CurrencyInfo{
+			"MYR": {ID: "MYR", CurrencyName: "Malaysian Ringgit", CurrencySymbol: "RM"},
+			"XYZ": {ID: "XYZ", CurrencyName: "Test", CurrencySymbol: "X"},
+			"USD": {ID: "USD", CurrencyName: "United States Dollar"},
+		},
+	})
+
+	assert.Equal(t, "X1,234.50", f.Format(NewMoney(MustParseDecimal("1234.5"), "XYZ"), "en-US"))
+	assert.Equal(t, "RM1,234.50", f.Format(NewMoney(MustParseDecimal("1234.5"), "MYR"), "en-MY"))
+	assert.Equal(t, "$1.00", f.Format(NewMoney(MustParseDecimal("1"), "USD"), "en-US"))
+	assert.Equal(t, "$1.00", NewFormatter(nil).Format(NewMoney(MustParseDecimal("1"), "USD"), "en-US"))
+}
@@ -1,6 +1,6 @@
 module github.com/kitloong/go-currency-converter-api/v2
 
-go 1.19
+go 1.21
 
 require github.com/stretchr/testify v1.8.1
 
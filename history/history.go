@@ -0,0 +1,286 @@
+// Package history is a file-based store of daily historical rates, synced incrementally from CurrencyConverterAPI.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	currconv "github.com/kitloong/go-currency-converter-api/v2"
+)
+
+// fileVersion is the version of the file format of a pair, increased on incompatible changes.
+const fileVersion = 1
+
+const dateLayout = "2006-01-02"
+
+// settleDays is the age in days from which a date without a rate is recorded as unavailable, rates of younger dates
+// could still be published later.
+const settleDays = 2
+
+// pairPattern matches a pair in "[FROM]_[TO]" format, which is also its file name.
+var pairPattern = regexp.MustCompile(`^[A-Z]{3}_[A-Z]{3}$`)
+
+// Rate is the rate of a pair on a date.
+type Rate struct {
+	Date time.Time
+	Rate currconv.Decimal
+}
+
+// pairFile is the file format of the rates of a pair.
+type pairFile struct {
+	Version int                         `json:"version"`
+	Pair    string                      `json:"pair"`
+	Rates   map[string]currconv.Decimal `json:"rates"`
+	// Unavailable are the dates at least settleDays old requested without a rate in the result, not requested again.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// Store keeps the daily rates of each pair in a JSON file of its directory, so that they survive restarts.
+// Files are replaced atomically, so that readers, in this process or another one, never see a partial file.
+// A directory must have a single writing process.
+type Store struct {
+	dir string
+	api *currconv.API
+	// mu serializes Sync, readers do not take it.
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// Open create and return a Store in `dir`, creating the directory if needed, syncing with `api`.
+func Open(dir string, api *currconv.API) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		dir: dir,
+		api: api,
+		now: time.Now,
+	}, nil
+}
+
+// Sync fetches the rates of `pairs` from `from` to `to` which are not in the store yet, with ConvertHistoricalMany
+// within MaxPairsPerRequest and MaxHistoricalDays. Pairs missing the same dates share requests.
+// Dates from today on are always fetched again, as their rates are not final, and dates after today are skipped.
+// A date without a rate is requested again on the next Sync until it is 2 days old, in case its rate is published late.
+// Rates fetched before an error are kept.
+func (s *Store) Sync(pairs []string, from, to time.Time) error {
+	if len(pairs) == 0 {
+		return errors.New("`pairs` require at least one currency conversion")
+	}
+
+	if from.IsZero() {
+		return errors.New("`from` is required")
+	}
+
+	from, to = day(from), day(to)
+	if to.IsZero() || to.Before(from) {
+		to = from
+	}
+
+	today := day(s.now())
+	if to.After(today) {
+		to = today
+	}
+
+	settled := today.AddDate(0, 0, -settleDays)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files := map[string]*pairFile{}
+	groups := map[[2]time.Time][]string{}
+	for _, pair := range pairs {
+		if _, ok := files[pair]; ok {
+			continue
+		}
+
+		f, err := s.read(pair)
+		if err != nil {
+			return err
+		}
+		files[pair] = f
+
+		for _, r := range missingRuns(f, from, to, today) {
+			groups[r] = append(groups[r], pair)
+		}
+	}
+
+	runs := make([][2]time.Time, 0, len(groups))
+	for r := range groups {
+		runs = append(runs, r)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i][0].Before(runs[j][0]) })
+
+	for _, r := range runs {
+		req := currconv.ConvertHistoricalRequest{Q: groups[r], Date: r[0]}
+		if r[1].After(r[0]) {
+			req.EndDate = r[1]
+		}
+
+		rates, err := s.api.ConvertHistoricalMany(req)
+		if err != nil {
+			return err
+		}
+
+		for _, pair := range groups[r] {
+			f := files[pair]
+			for d := r[0]; !d.After(r[1]); d = d.AddDate(0, 0, 1) {
+				date := d.Format(dateLayout)
+				if rate, ok := rates[pair][date]; ok {
+					f.Rates[date] = rate
+				} else if !d.After(settled) {
+					f.Unavailable = append(f.Unavailable, date)
+				}
+			}
+			sort.Strings(f.Unavailable)
+
+			if err := s.write(f); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Range returns the stored rates of `pair` from `from` to `to`, sorted by date. Dates without a stored rate are omitted.
+func (s *Store) Range(pair string, from, to time.Time) ([]Rate, error) {
+	f, err := s.read(pair)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to = day(from), day(to)
+	if to.IsZero() || to.Before(from) {
+		to = from
+	}
+
+	var rates []Rate
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if rate, ok := f.Rates[d.Format(dateLayout)]; ok {
+			rates = append(rates, Rate{Date: d, Rate: rate})
+		}
+	}
+
+	return rates, nil
+}
+
+// Rate returns the stored rate of `pair` on `date`.
+// The second return value reports whether the rate is stored.
+func (s *Store) Rate(pair string, date time.Time) (currconv.Decimal, bool, error) {
+	f, err := s.read(pair)
+	if err != nil {
+		return currconv.Decimal{}, false, err
+	}
+
+	rate, ok := f.Rates[day(date).Format(dateLayout)]
+	return rate, ok, nil
+}
+
+// missingRuns returns the runs of consecutive dates from `from` to `to` to fetch for `f`.
+func missingRuns(f *pairFile, from, to, today time.Time) [][2]time.Time {
+	unavailable := map[string]bool{}
+	for _, date := range f.Unavailable {
+		unavailable[date] = true
+	}
+
+	var runs [][2]time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		date := d.Format(dateLayout)
+		if _, ok := f.Rates[date]; (ok || unavailable[date]) && d.Before(today) {
+			continue
+		}
+
+		if n := len(runs); n > 0 && runs[n-1][1].AddDate(0, 0, 1).Equal(d) {
+			runs[n-1][1] = d
+			continue
+		}
+		runs = append(runs, [2]time.Time{d, d})
+	}
+
+	return runs
+}
+
+// read returns the stored file of `pair`, empty if it does not exist yet.
+func (s *Store) read(pair string) (*pairFile, error) {
+	if !pairPattern.MatchString(pair) {
+		return nil, fmt.Errorf("invalid pair %q", pair)
+	}
+
+	f := &pairFile{Version: fileVersion, Pair: pair, Rates: map[string]currconv.Decimal{}}
+
+	b, err := os.ReadFile(s.path(pair))
+	if errors.Is(err, fs.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, f); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path(pair), err)
+	}
+
+	if f.Version != fileVersion {
+		return nil, fmt.Errorf("%s: unsupported version %d", s.path(pair), f.Version)
+	}
+
+	if f.Rates == nil {
+		f.Rates = map[string]currconv.Decimal{}
+	}
+
+	return f, nil
+}
+
+// write replaces the stored file of `f.Pair` atomically.
+func (s *Store) write(f *pairFile) error {
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, f.Pair+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(f.Pair))
+}
+
+// path returns the path of the file of `pair`.
+func (s *Store) path(pair string) string {
+	return filepath.Join(s.dir, pair+".json")
+}
+
+// day returns the UTC date of `t` at midnight, zero if `t` is zero.
+func day(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
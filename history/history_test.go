@@ -0,0 +1,239 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	currconv "github.com/kitloong/go-currency-converter-api/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// base is the integer part of the rates served by historicalServer, by source currency.
+var base = map[string]int{"USD": 4, "EUR": 5, "SGD": 3}
+
+// historicalServer serves compact historical rates of every date except Sundays, recording "q date endDate" of each request.
+func historicalServer(t *testing.T) (*httptest.Server, func() []string) {
+	return publishingServer(t, func() time.Time { return time.Time{} })
+}
+
+// publishingServer is a historicalServer which has not published the rates after `published` yet, if it is not zero.
+func publishingServer(t *testing.T, published func() time.Time) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var received []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		mu.Lock()
+		received = append(received, strings.TrimSpace(q.Get("q")+" "+q.Get("date")+" "+q.Get("endDate")))
+		mu.Unlock()
+
+		start, err := time.Parse("2006-01-02", q.Get("date"))
+		assert.NoError(t, err)
+		end := start
+		if q.Get("endDate") != "" {
+			end, err = time.Parse("2006-01-02", q.Get("endDate"))
+			assert.NoError(t, err)
+		}
+
+		result := map[string]map[string]json.RawMessage{}
+		for _, pair := range strings.Split(q.Get("q"), ",") {
+			result[pair] = map[string]json.RawMessage{}
+			for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+				if last := published(); d.Weekday() != time.Sunday && (last.IsZero() || !d.After(last)) {
+					result[pair][d.Format("2006-01-02")] = json.RawMessage(fmt.Sprintf("%d.%02d", base[pair[:3]], d.Day()))
+				}
+			}
+		}
+
+		b, _ := json.Marshal(result)
+		_, _ = w.Write(b)
+	}))
+
+	return ts, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}
+}
+
+func date(day int) time.Time {
+	return time.Date(2023, 2, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestStore(t *testing.T) {
+	ts, received := historicalServer(t)
+	defer ts.Close()
+
+	api := currconv.NewAPI(currconv.Config{
+		BaseURL:            ts.URL,
+		APIKey:             "key",
+		Version:            "v1",
+		MaxPairsPerRequest: 2,
+		MaxHistoricalDays:  8,
+	})
+
+	dir := t.TempDir()
+	s, err := Open(dir, api)
+	assert.NoError(t, err)
+	s.now = func() time.Time { return date(28) }
+
+	assert.NoError(t, s.Sync([]string{"USD_MYR", "EUR_MYR"}, date(1), date(10)))
+	assert.Equal(t, []string{
+		"EUR_MYR,USD_MYR 2023-02-01 2023-02-08",
+		"EUR_MYR,USD_MYR 2023-02-09 2023-02-10",
+	}, received())
+
+	rates, err := s.Range("EUR_MYR", date(4), date(6))
+	assert.NoError(t, err)
+	assert.Equal(t, []Rate{
+		{Date: date(4), Rate: currconv.MustParseDecimal("5.04")},
+		{Date: date(6), Rate: currconv.MustParseDecimal("5.06")},
+	}, rates, "2023-02-05 is a Sunday without a rate")
+
+	assert.NoError(t, s.Sync([]string{"USD_MYR", "EUR_MYR"}, date(1), date(10)))
+	assert.Len(t, received(), 2, "stored dates, and dates without a rate, are not fetched again")
+
+	assert.NoError(t, s.Sync([]string{"USD_MYR", "SGD_MYR"}, date(9), date(12)))
+	assert.Equal(t, []string{
+		"SGD_MYR 2023-02-09 2023-02-12",
+		"USD_MYR 2023-02-11 2023-02-12",
+	}, received()[2:], "only missing dates are fetched")
+
+	restarted, err := Open(dir, currconv.NewAPI(currconv.Config{BaseURL: "/unreachable/"}))
+	assert.NoError(t, err)
+	rate, ok, err := restarted.Rate("SGD_MYR", time.Date(2023, 2, 11, 15, 30, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "3.11", rate.String(), "rates survive a restart")
+
+	_, ok, err = restarted.Rate("SGD_MYR", date(20))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	rates, err = restarted.Range("GBP_MYR", date(1), date(10))
+	assert.NoError(t, err)
+	assert.Empty(t, rates)
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3, "no temporary file is left behind")
+}
+
+func TestStore_Today(t *testing.T) {
+	ts, received := historicalServer(t)
+	defer ts.Close()
+
+	api := currconv.NewAPI(currconv.Config{BaseURL: ts.URL, APIKey: "key", Version: "v1"})
+	s, err := Open(t.TempDir(), api)
+	assert.NoError(t, err)
+	s.now = func() time.Time { return time.Date(2023, 2, 14, 9, 0, 0, 0, time.UTC) }
+
+	assert.NoError(t, s.Sync([]string{"USD_MYR"}, date(12), date(20)))
+	assert.NoError(t, s.Sync([]string{"USD_MYR"}, date(12), date(20)))
+	assert.Equal(t, []string{
+		"USD_MYR 2023-02-12 2023-02-14",
+		"USD_MYR 2023-02-14",
+	}, received(), "today is fetched again, and dates after today are skipped")
+}
+
+func TestStore_LatePublication(t *testing.T) {
+	var mu sync.Mutex
+	published := date(12)
+	ts, received := publishingServer(t, func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return published
+	})
+	defer ts.Close()
+
+	api := currconv.NewAPI(currconv.Config{BaseURL: ts.URL, APIKey: "key", Version: "v1"})
+	s, err := Open(t.TempDir(), api)
+	assert.NoError(t, err)
+	s.now = func() time.Time { return time.Date(2023, 2, 14, 9, 0, 0, 0, time.UTC) }
+
+	assert.NoError(t, s.Sync([]string{"USD_MYR"}, date(10), date(13)))
+	_, ok, err := s.Rate("USD_MYR", date(13))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	mu.Lock()
+	published = date(13)
+	mu.Unlock()
+
+	assert.NoError(t, s.Sync([]string{"USD_MYR"}, date(10), date(13)))
+	rate, ok, err := s.Rate("USD_MYR", date(13))
+	assert.NoError(t, err)
+	assert.True(t, ok, "a rate published late is fetched on a later sync")
+	assert.Equal(t, "4.13", rate.String())
+
+	assert.NoError(t, s.Sync([]string{"USD_MYR"}, date(10), date(13)))
+	assert.Equal(t, []string{
+		"USD_MYR 2023-02-10 2023-02-13",
+		"USD_MYR 2023-02-13",
+	}, received(), "2023-02-12, a settled Sunday without a rate, is not requested again")
+}
+
+func TestStore_Errors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status": 400, "error": "Invalid API key"}`))
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	s, err := Open(dir, currconv.NewAPI(currconv.Config{BaseURL: ts.URL, APIKey: "key", Version: "v1"}))
+	assert.NoError(t, err)
+
+	assert.EqualError(t, s.Sync(nil, date(1), date(2)), "`pairs` require at least one currency conversion")
+	assert.EqualError(t, s.Sync([]string{"USD_MYR"}, time.Time{}, date(2)), "`from` is required")
+	assert.EqualError(t, s.Sync([]string{"../USD_MYR"}, date(1), date(2)), `invalid pair "../USD_MYR"`)
+	assert.EqualError(t, s.Sync([]string{"USD_MYR"}, date(1), date(2)), "Invalid API key")
+
+	_, err = s.Range("usd_myr", date(1), date(2))
+	assert.EqualError(t, err, `invalid pair "usd_myr"`)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "USD_MYR.json"), []byte(`{"version": 2}`), 0o644))
+	_, err = s.Range("USD_MYR", date(1), date(2))
+	assert.EqualError(t, err, filepath.Join(dir, "USD_MYR.json")+": unsupported version 2")
+}
+
+func TestStore_ConcurrentReaders(t *testing.T) {
+	ts, _ := historicalServer(t)
+	defer ts.Close()
+
+	api := currconv.NewAPI(currconv.Config{BaseURL: ts.URL, APIKey: "key", Version: "v1", MaxHistoricalDays: 2})
+	s, err := Open(t.TempDir(), api)
+	assert.NoError(t, err)
+	s.now = func() time.Time { return date(28) }
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				rates, err := s.Range("USD_MYR", date(1), date(27))
+				assert.NoError(t, err)
+				for _, r := range rates {
+					assert.Equal(t, fmt.Sprintf("4.%02d", r.Date.Day()), r.Rate.String())
+				}
+			}
+		}()
+	}
+
+	assert.NoError(t, s.Sync([]string{"USD_MYR"}, date(1), date(27)))
+	wg.Wait()
+
+	rates, err := s.Range("USD_MYR", date(1), date(27))
+	assert.NoError(t, err)
+	assert.Len(t, rates, 23)
+}
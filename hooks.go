@@ -0,0 +1,123 @@
+package currconv
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Endpoint names of RequestInfo.
+const (
+	EndpointConvert    = "convert"
+	EndpointCurrencies = "currencies"
+	EndpointCountries  = "countries"
+	EndpointUsage      = "usage"
+)
+
+// redacted replaces the API key in RequestInfo.URL.
+const redacted = "REDACTED"
+
+// RequestInfo describes a request to CurrencyConverterAPI, passed to Hooks.
+type RequestInfo struct {
+	// Endpoint is one of EndpointConvert, EndpointCurrencies, EndpointCountries or EndpointUsage.
+	Endpoint string
+	// URL is the request URL with the API key replaced by "REDACTED".
+	URL string
+	// Pairs is the number of pairs in `q`, 0 for endpoints other than convert.
+	Pairs int
+	// Date and EndDate are the dates of a historical request in "2006-01-02" format, empty otherwise.
+	Date    string
+	EndDate string
+	// Duration is the time from sending the request to reading the response body, 0 in OnRequest.
+	Duration time.Duration
+	// StatusCode is the HTTP status code of the response, 0 in OnRequest or if no response was received.
+	StatusCode int
+	// Err is the error returned to the caller with the API key replaced by "REDACTED", nil in OnRequest.
+	Err error
+}
+
+// Hooks are functions run for every request sent to CurrencyConverterAPI, e.g. to collect metrics.
+// Hooks run synchronously on the calling goroutine, so they must be fast and safe for concurrent use.
+// Any of them could be nil.
+type Hooks struct {
+	// OnRequest runs before a request is sent.
+	OnRequest func(info RequestInfo)
+	// OnResponse runs after a response is received, whatever its status code.
+	OnResponse func(info RequestInfo)
+	// OnError runs when a request fails, with or without a response, e.g. a network error, a non-200 status code
+	// or an invalid body. It runs after OnResponse if a response was received.
+	OnError func(info RequestInfo)
+	// OnCircuitChange runs when Config.CircuitBreaker changes its state.
+	OnCircuitChange func(from, to CircuitState)
+}
+
+// requestInfo returns the RequestInfo of a request to `u` at `path`.
+func requestInfo(path string, u *url.URL) RequestInfo {
+	endpoint := path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		endpoint = path[i+1:]
+	}
+
+	q := u.Query()
+	pairs := 0
+	if v := q.Get("q"); v != "" {
+		pairs = len(strings.Split(v, ","))
+	}
+
+	if q.Has("apiKey") {
+		q.Set("apiKey", redacted)
+	}
+	r := *u
+	r.RawQuery = q.Encode()
+
+	return RequestInfo{
+		Endpoint: endpoint,
+		URL:      r.String(),
+		Pairs:    pairs,
+		Date:     q.Get("date"),
+		EndDate:  q.Get("endDate"),
+	}
+}
+
+// finish sets the Duration and Err of `info`, runs OnResponse and OnError, and records it to Config.Metrics and Config.Logger.
+// `body` is the response body, nil if it was not read. It returns `err`.
+func (a *API) finish(info RequestInfo, start time.Time, body []byte, err error) error {
+	info.Duration = time.Since(start)
+	info.Err = a.redactError(err)
+
+	a.log(info, body)
+
+	if a.config.Metrics != nil {
+		a.config.Metrics.observeRequest(info)
+	}
+
+	h := a.config.Hooks
+	if info.StatusCode != 0 && h.OnResponse != nil {
+		h.OnResponse(info)
+	}
+
+	if err != nil && h.OnError != nil {
+		h.OnError(info)
+	}
+
+	return err
+}
+
+// redactError returns `err` with the API key replaced by "REDACTED" in its message, e.g. in the URL of a network error.
+// A *url.Error keeps its type and wrapped error, so that errors.Is, errors.As and Timeout still work.
+func (a *API) redactError(err error) error {
+	if err == nil || a.config.APIKey == "" || !strings.Contains(err.Error(), a.config.APIKey) {
+		return err
+	}
+
+	if ue, ok := err.(*url.Error); ok {
+		r := *ue
+		r.URL = a.redact(ue.URL)
+		if !strings.Contains(r.Error(), a.config.APIKey) {
+			return &r
+		}
+	}
+
+	return errors.New(a.redact(err.Error()))
+}
@@ -0,0 +1,179 @@
+package currconv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// hookRecorder records the RequestInfo passed to each hook.
+type hookRecorder struct {
+	mu     sync.Mutex
+	events []string
+	infos  []RequestInfo
+}
+
+func (h *hookRecorder) hooks() Hooks {
+	record := func(event string) func(RequestInfo) {
+		return func(info RequestInfo) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.events = append(h.events, event)
+			h.infos = append(h.infos, info)
+		}
+	}
+
+	return Hooks{
+		OnRequest:  record("request"),
+		OnResponse: record("response"),
+		OnError:    record("error"),
+	}
+}
+
+func TestHooks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/convert":
+			_, _ = w.Write([]byte(`{"MYR_USD": 0.229964, "USD_MYR": 4.348493}`))
+		case "/api/v1/currencies":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status": 400, "error": "Invalid API key"}`))
+		case "/api/v1/countries":
+			_, _ = w.Write([]byte(`{"results": `))
+		case "/others/usage":
+			_, _ = w.Write([]byte(`{"timestamp": "2023-02-15T02:05:49.988Z", "usage": 1}`))
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		call     func(api *API) error
+		events   []string
+		endpoint string
+		url      string
+		pairs    int
+		status   int
+		error    string
+	}{
+		{
+			"Convert",
+			func(api *API) error {
+				_, err := api.ConvertCompact(ConvertRequest{Q: []string{"MYR_USD", "USD_MYR"}})
+				return err
+			},
+			[]string{"request", "response"},
+			EndpointConvert,
+			ts.URL + "/api/v1/convert?apiKey=REDACTED&compact=ultra&q=MYR_USD%2CUSD_MYR",
+			2,
+			http.StatusOK,
+			"",
+		},
+		{
+			"Error status",
+			func(api *API) error {
+				_, err := api.Currencies()
+				return err
+			},
+			[]string{"request", "response", "error"},
+			EndpointCurrencies,
+			ts.URL + "/api/v1/currencies?apiKey=REDACTED",
+			0,
+			http.StatusBadRequest,
+			"Invalid API key",
+		},
+		{
+			"Invalid body",
+			func(api *API) error {
+				_, err := api.Countries()
+				return err
+			},
+			[]string{"request", "response", "error"},
+			EndpointCountries,
+			ts.URL + "/api/v1/countries?apiKey=REDACTED",
+			0,
+			http.StatusOK,
+			"unexpected end of JSON input",
+		},
+		{
+			"Usage",
+			func(api *API) error {
+				_, err := api.Usage()
+				return err
+			},
+			[]string{"request", "response"},
+			EndpointUsage,
+			ts.URL + "/others/usage?apiKey=REDACTED",
+			0,
+			http.StatusOK,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &hookRecorder{}
+			api := NewAPI(Config{
+				BaseURL: ts.URL,
+				APIKey:  "secret",
+				Version: "v1",
+				Hooks:   r.hooks(),
+			})
+
+			err := tt.call(api)
+			if tt.error != "" {
+				assert.EqualError(t, err, tt.error)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.events, r.events)
+			for i, info := range r.infos {
+				assert.Equal(t, tt.endpoint, info.Endpoint)
+				assert.Equal(t, tt.url, info.URL)
+				assert.NotContains(t, info.URL, "secret")
+				assert.Equal(t, tt.pairs, info.Pairs)
+
+				if i == 0 {
+					assert.Zero(t, info.StatusCode)
+					assert.Zero(t, info.Duration)
+					assert.NoError(t, info.Err)
+					continue
+				}
+
+				assert.Equal(t, tt.status, info.StatusCode)
+				assert.Positive(t, info.Duration)
+				if tt.error != "" {
+					assert.EqualError(t, info.Err, tt.error)
+				}
+			}
+		})
+	}
+}
+
+func TestHooks_NetworkError(t *testing.T) {
+	r := &hookRecorder{}
+	api := NewAPI(Config{
+		BaseURL: "/error/",
+		APIKey:  "secret",
+		Version: "v1",
+		Hooks:   r.hooks(),
+	})
+
+	_, err := api.Usage()
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"request", "error"}, r.events)
+	assert.Zero(t, r.infos[1].StatusCode)
+	assert.Equal(t, strings.ReplaceAll(err.Error(), "secret", "REDACTED"), r.infos[1].Err.Error())
+	assert.NotContains(t, r.infos[1].Err.Error(), "secret")
+	assert.Equal(t, "/error/others/usage?apiKey=REDACTED", r.infos[1].URL)
+
+	var ue *url.Error
+	assert.ErrorAs(t, r.infos[1].Err, &ue, "a network error keeps its type")
+}
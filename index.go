@@ -0,0 +1,341 @@
+package currconv
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// foldRunes maps accented Latin letters to their base letters, used to compare names accent-insensitively.
+var foldRunes = func() map[rune]string {
+	m := map[rune]string{}
+	for base, accented := range map[string]string{
+		"a": "àáâãäåāăąǎ", "c": "çćĉċč", "d": "ďđð", "e": "èéêëēĕėęě", "g": "ĝğġģ", "h": "ĥħ",
+		"i": "ìíîïĩīĭįı", "j": "ĵ", "k": "ķ", "l": "ĺļľŀł", "n": "ñńņňŉ", "o": "òóôõöøōŏőǒ",
+		"r": "ŕŗř", "s": "śŝşšș", "t": "ţťŧț", "u": "ùúûüũūŭůűųǔ", "w": "ŵ", "y": "ýÿŷ", "z": "źżž",
+		"ae": "æ", "oe": "œ", "ss": "ß", "th": "þ",
+	} {
+		for _, r := range accented {
+			m[r] = base
+		}
+	}
+
+	return m
+}()
+
+// Index looks up countries and currencies by code and name, built from the results of Countries and Currencies.
+// Names are compared case- and accent-insensitively, e.g. "cote d'ivoire" finds "Côte d'Ivoire".
+type Index struct {
+	countries         map[string]CountryInfo
+	currencies        map[string]CurrencyInfo
+	alpha3            map[string]string
+	countryNames      map[string]string
+	currencyNames     map[string]string
+	currencyCountries map[string][]string
+}
+
+// NewIndex create and return an Index.
+// `currencies` is optional, currencies are then taken from `countries`, e.g. NewIndex(nil, DatasetCountries()).
+func NewIndex(currencies *Currency, countries *Country) *Index {
+	i := &Index{
+		countries:         map[string]CountryInfo{},
+		currencies:        map[string]CurrencyInfo{},
+		alpha3:            map[string]string{},
+		countryNames:      map[string]string{},
+		currencyNames:     map[string]string{},
+		currencyCountries: map[string][]string{},
+	}
+
+	if countries != nil {
+		ids := make([]string, 0, len(countries.Results))
+		for id := range countries.Results {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			c := countries.Results[id]
+			i.countries[id] = c
+			i.alpha3[c.Alpha3] = id
+			if _, ok := i.countryNames[foldName(c.Name)]; !ok {
+				i.countryNames[foldName(c.Name)] = id
+			}
+
+			if c.CurrencyID != "" {
+				i.currencyCountries[c.CurrencyID] = append(i.currencyCountries[c.CurrencyID], id)
+				if _, ok := i.currencies[c.CurrencyID]; !ok {
+					i.currencies[c.CurrencyID] = CurrencyInfo{ID: c.CurrencyID, CurrencyName: c.CurrencyName, CurrencySymbol: c.CurrencySymbol}
+				}
+			}
+		}
+	}
+
+	if currencies != nil {
+		for id, c := range currencies.Results {
+			i.currencies[id] = c
+		}
+	}
+
+	ids := make([]string, 0, len(i.currencies))
+	for id := range i.currencies {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if name := foldName(i.currencies[id].CurrencyName); name != "" {
+			if _, ok := i.currencyNames[name]; !ok {
+				i.currencyNames[name] = id
+			}
+		}
+	}
+
+	return i
+}
+
+// Country returns the country with ISO 3166-1 alpha-2 or alpha-3 code `code`, case-insensitive.
+// The second return value reports whether the country exists.
+func (i *Index) Country(code string) (CountryInfo, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if id, ok := i.alpha3[code]; ok && len(code) == 3 {
+		code = id
+	}
+
+	c, ok := i.countries[code]
+	return c, ok
+}
+
+// CountryByName returns the country named `name`, case- and accent-insensitive.
+// The second return value reports whether the country exists.
+func (i *Index) CountryByName(name string) (CountryInfo, bool) {
+	id, ok := i.countryNames[foldName(name)]
+	if !ok {
+		return CountryInfo{}, false
+	}
+
+	return i.countries[id], true
+}
+
+// Currency returns the currency with ISO 4217 code `code`, case-insensitive.
+// The second return value reports whether the currency exists.
+func (i *Index) Currency(code string) (CurrencyInfo, bool) {
+	c, ok := i.currencies[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+// CurrencyByName returns the currency named `name`, case- and accent-insensitive.
+// The second return value reports whether the currency exists.
+func (i *Index) CurrencyByName(name string) (CurrencyInfo, bool) {
+	id, ok := i.currencyNames[foldName(name)]
+	if !ok {
+		return CurrencyInfo{}, false
+	}
+
+	return i.currencies[id], true
+}
+
+// CurrencyOf returns the currency of the country with alpha-2 or alpha-3 code `country`.
+// The second return value reports whether the country and its currency exist.
+func (i *Index) CurrencyOf(country string) (CurrencyInfo, bool) {
+	c, ok := i.Country(country)
+	if !ok {
+		return CurrencyInfo{}, false
+	}
+
+	return i.Currency(c.CurrencyID)
+}
+
+// CountriesOf returns the countries using the currency with ISO 4217 code `currency`, sorted by alpha-2 code.
+func (i *Index) CountriesOf(currency string) []CountryInfo {
+	ids := i.currencyCountries[strings.ToUpper(strings.TrimSpace(currency))]
+	countries := make([]CountryInfo, len(ids))
+	for n, id := range ids {
+		countries[n] = i.countries[id]
+	}
+
+	return countries
+}
+
+// SearchCountries returns up to `limit` countries best matching `query` for autocomplete, all matches if `limit` is 0.
+// Codes and names are matched case- and accent-insensitively, in order of exact matches, name prefixes, word prefixes,
+// substrings, and then names within a small edit distance to tolerate typos.
+func (i *Index) SearchCountries(query string, limit int) []CountryInfo {
+	var matches []match
+	for id, c := range i.countries {
+		if s, ok := matchScore(query, c.Name, id, c.Alpha3); ok {
+			matches = append(matches, match{id: id, name: foldName(c.Name), score: s})
+		}
+	}
+
+	ids := sortMatches(matches, limit)
+	countries := make([]CountryInfo, len(ids))
+	for n, id := range ids {
+		countries[n] = i.countries[id]
+	}
+
+	return countries
+}
+
+// SearchCurrencies returns up to `limit` currencies best matching `query` for autocomplete, all matches if `limit` is 0.
+// See SearchCountries for the matching rules.
+func (i *Index) SearchCurrencies(query string, limit int) []CurrencyInfo {
+	var matches []match
+	for id, c := range i.currencies {
+		if s, ok := matchScore(query, c.CurrencyName, id); ok {
+			matches = append(matches, match{id: id, name: foldName(c.CurrencyName), score: s})
+		}
+	}
+
+	ids := sortMatches(matches, limit)
+	currencies := make([]CurrencyInfo, len(ids))
+	for n, id := range ids {
+		currencies[n] = i.currencies[id]
+	}
+
+	return currencies
+}
+
+// match is a search candidate, lower score is better.
+type match struct {
+	id    string
+	name  string
+	score int
+}
+
+// sortMatches sorts `matches` by score and then by name, and returns the ids of the first `limit` matches.
+func sortMatches(matches []match, limit int) []string {
+	sort.Slice(matches, func(a, b int) bool {
+		if matches[a].score != matches[b].score {
+			return matches[a].score < matches[b].score
+		}
+		return matches[a].name < matches[b].name
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	ids := make([]string, len(matches))
+	for n, m := range matches {
+		ids[n] = m.id
+	}
+
+	return ids
+}
+
+// matchScore scores how well `query` matches `name` or one of `codes`.
+// The second return value reports whether it matches at all.
+func matchScore(query string, name string, codes ...string) (int, bool) {
+	q := foldName(query)
+	if q == "" {
+		return 0, false
+	}
+
+	for _, code := range codes {
+		if strings.EqualFold(strings.TrimSpace(query), code) {
+			return 0, true
+		}
+	}
+
+	n := foldName(name)
+	switch {
+	case n == q:
+		return 0, true
+	case strings.HasPrefix(n, q):
+		return 1, true
+	case strings.Contains(n, " "+q):
+		return 2, true
+	case strings.Contains(n, q):
+		return 3, true
+	}
+
+	// Typos are tolerated by comparing the query with the beginning of the name, and of each word of the name.
+	// Beginnings one rune shorter or longer than the query cover a missing or an extra letter.
+	size := len([]rune(q))
+	allowed := size / 4
+	if allowed == 0 {
+		return 0, false
+	}
+
+	best := allowed + 1
+	for _, w := range append([]string{n}, strings.Fields(n)...) {
+		for l := size - 1; l <= size+1; l++ {
+			if d := editDistance(q, runePrefix(w, l)); d < best {
+				best = d
+			}
+		}
+	}
+
+	if best > allowed {
+		return 0, false
+	}
+
+	return 4 + best, true
+}
+
+// foldName lower-cases `s`, removes accents and apostrophes, and replaces other punctuation with a single space.
+func foldName(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r == '\'' || r == '’' || r == 'ʻ':
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			if f, ok := foldRunes[r]; ok {
+				b.WriteString(f)
+			} else {
+				b.WriteRune(r)
+			}
+		default:
+			space = true
+		}
+	}
+
+	return b.String()
+}
+
+// runePrefix returns the first `n` runes of `s`.
+func runePrefix(s string, n int) string {
+	r := []rune(s)
+	if len(r) > n {
+		r = r[:n]
+	}
+
+	return string(r)
+}
+
+// editDistance returns the Levenshtein distance between `a` and `b`.
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = prev[j] + 1
+			if c := curr[j-1] + 1; c < curr[j] {
+				curr[j] = c
+			}
+			if c := prev[j-1] + cost; c < curr[j] {
+				curr[j] = c
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
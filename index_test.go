@@ -0,0 +1,125 @@
+package currconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func countryIDs(countries []CountryInfo) []string {
+	ids := make([]string, len(countries))
+	for i, c := range countries {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+func TestIndex_Country(t *testing.T) {
+	index := NewIndex(DatasetCurrencies(), DatasetCountries())
+
+	tests := []struct {
+		name   string
+		lookup func() (CountryInfo, bool)
+		id     string
+	}{
+		{"Alpha-2", func() (CountryInfo, bool) { return index.Country("MY") }, "MY"},
+		{"Alpha-2 lower case", func() (CountryInfo, bool) { return index.Country("my") }, "MY"},
+		{"Alpha-3", func() (CountryInfo, bool) { return index.Country("mys") }, "MY"},
+		{"Unknown code", func() (CountryInfo, bool) { return index.Country("ZZ") }, ""},
+		{"Name", func() (CountryInfo, bool) { return index.CountryByName("malaysia") }, "MY"},
+		{"Name without accent", func() (CountryInfo, bool) { return index.CountryByName("Cote d'Ivoire") }, "CI"},
+		{"Name without punctuation", func() (CountryInfo, bool) { return index.CountryByName("COTE DIVOIRE") }, "CI"},
+		{"Name with accent", func() (CountryInfo, bool) { return index.CountryByName("Réunion") }, "RE"},
+		{"Unknown name", func() (CountryInfo, bool) { return index.CountryByName("Atlantis") }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ok := tt.lookup()
+			assert.Equal(t, tt.id != "", ok)
+			assert.Equal(t, tt.id, c.ID)
+		})
+	}
+}
+
+func TestIndex_Currency(t *testing.T) {
+	index := NewIndex(DatasetCurrencies(), DatasetCountries())
+
+	c, ok := index.Currency("myr")
+	assert.True(t, ok)
+	assert.Equal(t, "Malaysian Ringgit", c.CurrencyName)
+
+	c, ok = index.CurrencyByName("costa rican colon")
+	assert.True(t, ok)
+	assert.Equal(t, "CRC", c.ID)
+
+	c, ok = index.CurrencyOf("JPN")
+	assert.True(t, ok)
+	assert.Equal(t, "JPY", c.ID)
+
+	_, ok = index.CurrencyOf("ZZ")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"CH", "LI"}, countryIDs(index.CountriesOf("chf")))
+	assert.Empty(t, index.CountriesOf("XXX"))
+}
+
+func TestIndex_CurrenciesFromCountries(t *testing.T) {
+	index := NewIndex(nil, &Country{Results: map[string]CountryInfo{
+		"MY": {ID: "MY", Alpha3: "MYS", CurrencyID: "MYR", CurrencyName: "Malaysian ringgit", CurrencySymbol: "RM", Name: "Malaysia"},
+	}})
+
+	c, ok := index.CurrencyByName("Malaysian Ringgit")
+	assert.True(t, ok)
+	assert.Equal(t, CurrencyInfo{ID: "MYR", CurrencyName: "Malaysian ringgit", CurrencySymbol: "RM"}, c)
+}
+
+func TestIndex_SearchCountries(t *testing.T) {
+	index := NewIndex(nil, DatasetCountries())
+
+	tests := []struct {
+		query string
+		limit int
+		ids   []string
+	}{
+		{"MYS", 0, []string{"MY"}},
+		{"malay", 0, []string{"MY", "MW"}},
+		{"new", 3, []string{"NC", "NZ", "PG"}},
+		{"guinea", 0, []string{"GN", "GW", "GQ", "PG"}},
+		{"cote", 0, []string{"CI"}},
+		{"Aland", 1, []string{"AX"}},
+		{"Malyasia", 0, []string{"MY"}},
+		{"Swizterland", 0, []string{"CH"}},
+		{"", 0, []string{}},
+		{"qqqqqqqq", 0, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.ids, countryIDs(index.SearchCountries(tt.query, tt.limit)))
+		})
+	}
+}
+
+func TestIndex_SearchCurrencies(t *testing.T) {
+	index := NewIndex(DatasetCurrencies(), nil)
+
+	var ids []string
+	for _, c := range index.SearchCurrencies("dinar", 3) {
+		ids = append(ids, c.ID)
+	}
+	assert.Equal(t, []string{"DZD", "BHD", "IQD"}, ids)
+
+	ids = nil
+	for _, c := range index.SearchCurrencies("ringit", 0) {
+		ids = append(ids, c.ID)
+	}
+	assert.Equal(t, []string{"MYR"}, ids)
+}
+
+func TestFoldName(t *testing.T) {
+	assert.Equal(t, "cote divoire", foldName("Côte d’Ivoire"))
+	assert.Equal(t, "guinea bissau", foldName(" Guinea-Bissau "))
+	assert.Equal(t, "saint barthelemy", foldName("Saint Barthélemy"))
+	assert.Equal(t, "tongan paanga", foldName("Tongan Paʻanga"))
+}
@@ -0,0 +1,134 @@
+// Command gendataset refreshes the embedded dataset from responses of the Currencies and Countries API.
+//
+// Save the responses of a live API first, then run `go generate` in the module root:
+//
+//	curl -o dataset/currencies.response.json "https://free.currconv.com/api/v7/currencies?apiKey=[KEY]"
+//	curl -o dataset/countries.response.json "https://free.currconv.com/api/v7/countries?apiKey=[KEY]"
+//	go generate ./...
+//
+// Names, symbols and countries are replaced by the responses.
+// Minor units and numeric codes are not returned by the API, they are kept from the current dataset,
+// new currencies default to 2 minor units without a numeric code and are reported to fill in by hand.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	currconv "github.com/kitloong/go-currency-converter-api/v2"
+)
+
+// currenciesFile is the embedded dataset of currencies.
+type currenciesFile struct {
+	Version string                          `json:"version"`
+	Results map[string]currconv.ISOCurrency `json:"results"`
+}
+
+// countriesFile is the embedded dataset of countries.
+type countriesFile struct {
+	Version string                          `json:"version"`
+	Results map[string]currconv.CountryInfo `json:"results"`
+}
+
+func main() {
+	currencies := flag.String("currencies", "", "path of the Currencies API response")
+	countries := flag.String("countries", "", "path of the Countries API response")
+	dir := flag.String("dir", "dataset", "directory of the embedded dataset")
+	version := flag.String("version", time.Now().UTC().Format("2006-01-02"), "version of the generated dataset")
+	flag.Parse()
+
+	missing, err := generate(*dir, *currencies, *countries, *version)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, id := range missing {
+		log.Printf("%s is new, fill in its minor units and numeric code in %s", id, filepath.Join(*dir, "currencies.json"))
+	}
+}
+
+// generate writes the dataset in `dir` from the responses at `currenciesPath` and `countriesPath`.
+// It returns the currencies without a numeric code, sorted.
+func generate(dir string, currenciesPath string, countriesPath string, version string) ([]string, error) {
+	var current currenciesFile
+	if err := readJSON(filepath.Join(dir, "currencies.json"), &current); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var currencyResp currconv.Currency
+	if err := readJSON(currenciesPath, &currencyResp); err != nil {
+		return nil, err
+	}
+
+	var countryResp currconv.Country
+	if err := readJSON(countriesPath, &countryResp); err != nil {
+		return nil, err
+	}
+
+	if len(currencyResp.Results) == 0 || len(countryResp.Results) == 0 {
+		return nil, fmt.Errorf("responses of %s and %s must not be empty", currenciesPath, countriesPath)
+	}
+
+	var missing []string
+	currencies := currenciesFile{Version: version, Results: map[string]currconv.ISOCurrency{}}
+	for id, info := range currencyResp.Results {
+		c, ok := current.Results[id]
+		if !ok {
+			c.MinorUnits = 2
+		}
+		if c.NumericCode == "" {
+			missing = append(missing, id)
+		}
+
+		c.CurrencyInfo = info
+		currencies.Results[id] = c
+	}
+	sort.Strings(missing)
+
+	for id, info := range countryResp.Results {
+		if _, ok := currencies.Results[info.CurrencyID]; !ok {
+			return nil, fmt.Errorf("currency %s of country %s is not in %s", info.CurrencyID, id, currenciesPath)
+		}
+	}
+
+	if err := writeJSON(filepath.Join(dir, "currencies.json"), currencies); err != nil {
+		return nil, err
+	}
+
+	return missing, writeJSON(filepath.Join(dir, "countries.json"), countriesFile{Version: version, Results: countryResp.Results})
+}
+
+// readJSON decodes the file at `path` into `v`.
+func readJSON(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
+
+// writeJSON writes `v` as indented JSON to `path` atomically.
+func writeJSON(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	write("currencies.json", `{
+		"version": "2020-01-01",
+		"results": {
+			"JPY": {"id": "JPY", "currencyName": "Yen", "currencySymbol": "¥", "minorUnits": 0, "numericCode": "392"},
+			"OLD": {"id": "OLD", "currencyName": "Old", "currencySymbol": "O", "minorUnits": 2, "numericCode": "999"}
+		}
+	}`)
+	currencies := write("currencies.response.json", `{"results": {
+		"JPY": {"id": "JPY", "currencyName": "Japanese Yen", "currencySymbol": "¥"},
+		"NEW": {"id": "NEW", "currencyName": "New", "currencySymbol": "N"}
+	}}`)
+	countries := write("countries.response.json", `{"results": {
+		"JP": {"id": "JP", "alpha3": "JPN", "currencyId": "JPY", "currencyName": "Japanese yen", "currencySymbol": "¥", "name": "Japan"}
+	}}`)
+
+	missing, err := generate(dir, currencies, countries, "2026-10-19")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NEW"}, missing)
+
+	var c currenciesFile
+	assert.NoError(t, readJSON(filepath.Join(dir, "currencies.json"), &c))
+	assert.Equal(t, "2026-10-19", c.Version)
+	assert.Len(t, c.Results, 2)
+	assert.Equal(t, "Japanese Yen", c.Results["JPY"].CurrencyName)
+	assert.Equal(t, int32(0), c.Results["JPY"].MinorUnits)
+	assert.Equal(t, "392", c.Results["JPY"].NumericCode)
+	assert.Equal(t, int32(2), c.Results["NEW"].MinorUnits)
+
+	var n countriesFile
+	assert.NoError(t, readJSON(filepath.Join(dir, "countries.json"), &n))
+	assert.Equal(t, "2026-10-19", n.Version)
+	assert.Equal(t, "JPN", n.Results["JP"].Alpha3)
+
+	countries = write("countries.response.json", `{"results": {
+		"XX": {"id": "XX", "alpha3": "XXX", "currencyId": "XXX", "name": "Nowhere"}
+	}}`)
+	_, err = generate(dir, currencies, countries, "2026-10-19")
+	assert.EqualError(t, err, "currency XXX of country XX is not in "+currencies)
+}
@@ -0,0 +1,351 @@
+// Package ledger is a multi-currency double-entry ledger, converting and revaluing balances with CurrencyConverterAPI.
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	currconv "github.com/kitloong/go-currency-converter-api/v2"
+)
+
+const (
+	// DefaultUnrealizedAccount is the account of unrealized gain and loss posted by Revalue.
+	DefaultUnrealizedAccount = "Unrealized FX Gain/Loss"
+	// ClearingAccountPrefix prefixes the clearing account of each currency used by Convert.
+	ClearingAccountPrefix = "FX Clearing:"
+	// defaultPlaces is the default number of digits after the decimal point of converted amounts.
+	defaultPlaces = 2
+)
+
+// Account holds a balance in a single currency.
+type Account struct {
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+// Posting is a single line of an Entry.
+type Posting struct {
+	Account string `json:"account"`
+	// Amount is in the currency of Account, positive for debit and negative for credit.
+	Amount currconv.Decimal `json:"amount"`
+	// Base is Amount in the base currency of the ledger, computed by Post.
+	Base currconv.Decimal `json:"base"`
+}
+
+// RateSnapshot is a rate used by an Entry.
+type RateSnapshot struct {
+	// Pair is in "[FROM]_[TO]" format.
+	Pair string           `json:"pair"`
+	Rate currconv.Decimal `json:"rate"`
+	// Date is the date of a historical rate, or the time a current rate was fetched.
+	Date time.Time `json:"date"`
+}
+
+// Entry is a balanced journal entry.
+type Entry struct {
+	ID          int       `json:"id"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Postings    []Posting `json:"postings"`
+	// Rates must contain "[CURRENCY]_[BASE]" of each foreign currency in Postings.
+	// Conversion entries also record the rate between the converted currencies.
+	Rates []RateSnapshot `json:"rates,omitempty"`
+}
+
+// Ledger is a multi-currency double-entry ledger.
+// All fields are exported so a Ledger could be persisted with encoding/json.
+type Ledger struct {
+	// Base is the currency foreign balances are revalued against.
+	Base     string             `json:"base"`
+	Accounts map[string]Account `json:"accounts"`
+	Entries  []Entry            `json:"entries"`
+	// UnrealizedAccount is the account Revalue posts gain and loss to, default to DefaultUnrealizedAccount.
+	UnrealizedAccount string `json:"unrealizedAccount,omitempty"`
+	// Places is the number of digits after the decimal point of converted amounts, default to 2.
+	Places int32 `json:"places,omitempty"`
+}
+
+// New create and return a Ledger in `base` currency.
+func New(base string) *Ledger {
+	return &Ledger{
+		Base:     base,
+		Accounts: map[string]Account{},
+	}
+}
+
+// Open opens an account in `currency`. Opening an existing account in the same currency is a no-op.
+func (l *Ledger) Open(name string, currency string) error {
+	if acc, ok := l.Accounts[name]; ok {
+		if acc.Currency != currency {
+			return fmt.Errorf("account %q is already opened in %s", name, acc.Currency)
+		}
+		return nil
+	}
+
+	if l.Accounts == nil {
+		l.Accounts = map[string]Account{}
+	}
+	l.Accounts[name] = Account{Name: name, Currency: currency}
+	return nil
+}
+
+// Post validates `e`, computes the Base of each posting with `e.Rates` and appends it to the ledger.
+// Postings of each currency must sum to zero.
+func (l *Ledger) Post(e Entry) (Entry, error) {
+	if len(e.Postings) == 0 {
+		return Entry{}, errors.New("`Postings` require at least one posting")
+	}
+
+	sums := map[string]currconv.Decimal{}
+	postings := make([]Posting, len(e.Postings))
+	for i, p := range e.Postings {
+		acc, ok := l.Accounts[p.Account]
+		if !ok {
+			return Entry{}, fmt.Errorf("account %q is not opened", p.Account)
+		}
+
+		p.Base = p.Amount
+		if acc.Currency != l.Base && !p.Amount.IsZero() {
+			rate, ok := findRate(e.Rates, acc.Currency+"_"+l.Base)
+			if !ok {
+				return Entry{}, fmt.Errorf("rate of %s_%s is required", acc.Currency, l.Base)
+			}
+			p.Base = p.Amount.Mul(rate.Rate).Round(l.places())
+		}
+
+		sums[acc.Currency] = sums[acc.Currency].Add(p.Amount)
+		postings[i] = p
+	}
+
+	for currency, sum := range sums {
+		if !sum.IsZero() {
+			return Entry{}, fmt.Errorf("postings in %s are not balanced by %s", currency, sum)
+		}
+	}
+
+	e.ID = len(l.Entries) + 1
+	e.Postings = postings
+	l.Entries = append(l.Entries, e)
+	return e, nil
+}
+
+// Balance returns the balance of `account` in its own currency.
+func (l *Ledger) Balance(account string) currconv.Decimal {
+	var balance currconv.Decimal
+	for _, e := range l.Entries {
+		for _, p := range e.Postings {
+			if p.Account == account {
+				balance = balance.Add(p.Amount)
+			}
+		}
+	}
+
+	return balance
+}
+
+// BaseBalance returns the carrying value of `account` in the base currency, including revaluations.
+func (l *Ledger) BaseBalance(account string) currconv.Decimal {
+	var balance currconv.Decimal
+	for _, e := range l.Entries {
+		for _, p := range e.Postings {
+			if p.Account == account {
+				balance = balance.Add(p.Base)
+			}
+		}
+	}
+
+	return balance
+}
+
+// Convert posts a conversion entry moving `amount` out of account `from` into account `to` in another currency.
+// Rates are fetched with ConvertMany, or ConvertHistoricalMany if `date` is before today, and recorded in the entry.
+// Both sides are balanced through the clearing account of each currency.
+func (l *Ledger) Convert(api *currconv.API, date time.Time, from string, to string, amount currconv.Decimal, description string) (Entry, error) {
+	fromAcc, ok := l.Accounts[from]
+	if !ok {
+		return Entry{}, fmt.Errorf("account %q is not opened", from)
+	}
+
+	toAcc, ok := l.Accounts[to]
+	if !ok {
+		return Entry{}, fmt.Errorf("account %q is not opened", to)
+	}
+
+	if fromAcc.Currency == toAcc.Currency {
+		return Entry{}, fmt.Errorf("accounts %q and %q are both in %s", from, to, fromAcc.Currency)
+	}
+
+	pair := fromAcc.Currency + "_" + toAcc.Currency
+	rates, err := l.fetchRates(api, date, []string{fromAcc.Currency, toAcc.Currency}, pair)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	rate, _ := findRate(rates, pair)
+	converted := amount.Mul(rate.Rate).Round(l.places())
+
+	for _, currency := range []string{fromAcc.Currency, toAcc.Currency} {
+		if err := l.Open(ClearingAccountPrefix+currency, currency); err != nil {
+			return Entry{}, err
+		}
+	}
+
+	return l.Post(Entry{
+		Date:        date,
+		Description: description,
+		Postings: []Posting{
+			{Account: from, Amount: amount.Neg()},
+			{Account: ClearingAccountPrefix + fromAcc.Currency, Amount: amount},
+			{Account: ClearingAccountPrefix + toAcc.Currency, Amount: converted.Neg()},
+			{Account: to, Amount: converted},
+		},
+		Rates: rates,
+	})
+}
+
+// Revalue revalues every foreign account, except clearing accounts, at the rate of `date`,
+// and posts the unrealized gain and loss against UnrealizedAccount in a single entry.
+// Rates are fetched with ConvertMany, or ConvertHistoricalMany if `date` is before today.
+// It returns nil if there is nothing to revalue.
+func (l *Ledger) Revalue(api *currconv.API, date time.Time) (*Entry, error) {
+	var accounts []string
+	for name, acc := range l.Accounts {
+		if acc.Currency != l.Base && !strings.HasPrefix(name, ClearingAccountPrefix) && !l.Balance(name).IsZero() {
+			accounts = append(accounts, name)
+		}
+	}
+	sort.Strings(accounts)
+
+	currencies := make([]string, len(accounts))
+	for i, name := range accounts {
+		currencies[i] = l.Accounts[name].Currency
+	}
+
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+
+	rates, err := l.fetchRates(api, date, currencies, "")
+	if err != nil {
+		return nil, err
+	}
+
+	unrealized := l.unrealizedAccount()
+	if err := l.Open(unrealized, l.Base); err != nil {
+		return nil, err
+	}
+
+	var postings []Posting
+	var total currconv.Decimal
+	for _, name := range accounts {
+		acc := l.Accounts[name]
+		rate, _ := findRate(rates, acc.Currency+"_"+l.Base)
+
+		diff := l.Balance(name).Mul(rate.Rate).Round(l.places()).Sub(l.BaseBalance(name))
+		if diff.IsZero() {
+			continue
+		}
+
+		postings = append(postings, Posting{Account: name, Base: diff})
+		total = total.Add(diff)
+	}
+
+	if len(postings) == 0 {
+		return nil, nil
+	}
+
+	postings = append(postings, Posting{Account: unrealized, Base: total.Neg()})
+
+	// Postings of a revaluation only carry Base, so they are appended without Post's rate lookup.
+	e := Entry{
+		ID:          len(l.Entries) + 1,
+		Date:        date,
+		Description: "Revaluation against " + l.Base,
+		Postings:    postings,
+		Rates:       rates,
+	}
+	l.Entries = append(l.Entries, e)
+	return &e, nil
+}
+
+// fetchRates fetches the rate of each foreign currency of `currencies` to the base currency, and `extra` if not empty.
+func (l *Ledger) fetchRates(api *currconv.API, date time.Time, currencies []string, extra string) ([]RateSnapshot, error) {
+	var q []string
+	seen := map[string]bool{}
+	for _, c := range currencies {
+		if pair := c + "_" + l.Base; c != l.Base && !seen[pair] {
+			q = append(q, pair)
+			seen[pair] = true
+		}
+	}
+	if extra != "" && !seen[extra] {
+		q = append(q, extra)
+	}
+
+	rates := make([]RateSnapshot, 0, len(q))
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if date.IsZero() || !date.Before(today) {
+		r, err := api.ConvertMany(currconv.ConvertRequest{Q: q})
+		if err != nil {
+			return nil, err
+		}
+
+		fetchedAt := time.Now().UTC()
+		for _, pair := range q {
+			rate, ok := r[pair]
+			if !ok {
+				return nil, fmt.Errorf("%s: %w", pair, currconv.ErrRateNotFound)
+			}
+			rates = append(rates, RateSnapshot{Pair: pair, Rate: rate, Date: fetchedAt})
+		}
+
+		return rates, nil
+	}
+
+	r, err := api.ConvertHistoricalMany(currconv.ConvertHistoricalRequest{Q: q, Date: date})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pair := range q {
+		rate, ok := r[pair][date.Format("2006-01-02")]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", pair, currconv.ErrRateNotFound)
+		}
+		rates = append(rates, RateSnapshot{Pair: pair, Rate: rate, Date: date})
+	}
+
+	return rates, nil
+}
+
+// places returns the configured Places or the default.
+func (l *Ledger) places() int32 {
+	if l.Places > 0 {
+		return l.Places
+	}
+
+	return defaultPlaces
+}
+
+// unrealizedAccount returns the configured UnrealizedAccount or the default.
+func (l *Ledger) unrealizedAccount() string {
+	if l.UnrealizedAccount != "" {
+		return l.UnrealizedAccount
+	}
+
+	return DefaultUnrealizedAccount
+}
+
+// findRate returns the snapshot of `pair` in `rates`.
+func findRate(rates []RateSnapshot, pair string) (RateSnapshot, bool) {
+	for _, r := range rates {
+		if r.Pair == pair {
+			return r, true
+		}
+	}
+
+	return RateSnapshot{}, false
+}
@@ -0,0 +1,153 @@
+package ledger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	currconv "github.com/kitloong/go-currency-converter-api/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// ratesServer serves compact current rates from `current`, and compact historical rates from `historical`.
+func ratesServer(current map[string]string, historical map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		result := map[string]interface{}{}
+		for _, pair := range strings.Split(q.Get("q"), ",") {
+			if q.Get("date") == "" {
+				result[pair] = json.RawMessage(current[pair])
+				continue
+			}
+			result[pair] = map[string]json.RawMessage{q.Get("date"): json.RawMessage(historical[pair])}
+		}
+
+		b, _ := json.Marshal(result)
+		_, _ = w.Write(b)
+	}))
+}
+
+func TestLedger(t *testing.T) {
+	ts := ratesServer(
+		map[string]string{"MYR_USD": "0.229964", "USD_MYR": "4.348493"},
+		map[string]string{"USD_MYR": "4.50"},
+	)
+	defer ts.Close()
+
+	api := currconv.NewAPI(currconv.Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	l := New("MYR")
+	assert.NoError(t, l.Open("Bank:MYR", "MYR"))
+	assert.NoError(t, l.Open("Bank:USD", "USD"))
+	assert.NoError(t, l.Open("Equity", "MYR"))
+	assert.NoError(t, l.Open("Equity", "MYR"))
+	assert.EqualError(t, l.Open("Equity", "USD"), "account \"Equity\" is already opened in MYR")
+
+	_, err := l.Post(Entry{
+		Description: "Opening",
+		Postings: []Posting{
+			{Account: "Bank:MYR", Amount: currconv.MustParseDecimal("1000")},
+			{Account: "Equity", Amount: currconv.MustParseDecimal("-1000")},
+		},
+	})
+	assert.NoError(t, err)
+
+	e, err := l.Convert(api, time.Time{}, "Bank:MYR", "Bank:USD", currconv.MustParseDecimal("435"), "Buy USD")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, e.ID)
+	assert.Len(t, e.Rates, 2)
+	assert.Equal(t, "100.03", l.Balance("Bank:USD").String())
+	assert.Equal(t, "434.98", l.BaseBalance("Bank:USD").String())
+	assert.Equal(t, "565", l.Balance("Bank:MYR").String())
+	assert.Equal(t, "-100.03", l.Balance(ClearingAccountPrefix+"USD").String())
+
+	r, err := l.Revalue(api, time.Date(2023, 2, 14, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []Posting{
+		{Account: "Bank:USD", Base: currconv.MustParseDecimal("15.16")},
+		{Account: DefaultUnrealizedAccount, Base: currconv.MustParseDecimal("-15.16")},
+	}, r.Postings)
+	assert.Equal(t, "USD_MYR", r.Rates[0].Pair)
+	assert.Equal(t, "100.03", l.Balance("Bank:USD").String())
+	assert.Equal(t, "450.14", l.BaseBalance("Bank:USD").String())
+
+	r, err = l.Revalue(api, time.Date(2023, 2, 14, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	b, err := json.Marshal(l)
+	assert.NoError(t, err)
+
+	restored := &Ledger{}
+	assert.NoError(t, json.Unmarshal(b, restored))
+	assert.Equal(t, "MYR", restored.Base)
+	assert.Len(t, restored.Entries, 3)
+	assert.Equal(t, "450.14", restored.BaseBalance("Bank:USD").String())
+	assert.Equal(t, "0.229964", restored.Entries[1].Rates[1].Rate.String())
+}
+
+func TestLedger_Error(t *testing.T) {
+	l := New("MYR")
+	assert.NoError(t, l.Open("Bank:MYR", "MYR"))
+	assert.NoError(t, l.Open("Cash:MYR", "MYR"))
+	assert.NoError(t, l.Open("Bank:USD", "USD"))
+
+	tests := []struct {
+		name     string
+		entry    Entry
+		errorMsg string
+	}{
+		{
+			"No postings",
+			Entry{},
+			"`Postings` require at least one posting",
+		},
+		{
+			"Account not opened",
+			Entry{Postings: []Posting{{Account: "Bank:SGD"}}},
+			"account \"Bank:SGD\" is not opened",
+		},
+		{
+			"Not balanced",
+			Entry{Postings: []Posting{
+				{Account: "Bank:MYR", Amount: currconv.MustParseDecimal("10")},
+				{Account: "Cash:MYR", Amount: currconv.MustParseDecimal("-9.50")},
+			}},
+			"postings in MYR are not balanced by 0.50",
+		},
+		{
+			"Rate required",
+			Entry{Postings: []Posting{
+				{Account: "Bank:USD", Amount: currconv.MustParseDecimal("10")},
+			}},
+			"rate of USD_MYR is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := l.Post(tt.entry)
+			assert.EqualError(t, err, tt.errorMsg)
+		})
+	}
+
+	api := currconv.NewAPI(currconv.Config{BaseURL: "/error/", APIKey: "key", Version: "v1"})
+
+	_, err := l.Convert(api, time.Time{}, "Bank:SGD", "Bank:USD", currconv.MustParseDecimal("1"), "")
+	assert.EqualError(t, err, "account \"Bank:SGD\" is not opened")
+
+	_, err = l.Convert(api, time.Time{}, "Bank:MYR", "Cash:MYR", currconv.MustParseDecimal("1"), "")
+	assert.EqualError(t, err, "accounts \"Bank:MYR\" and \"Cash:MYR\" are both in MYR")
+
+	_, err = l.Convert(api, time.Time{}, "Bank:MYR", "Bank:USD", currconv.MustParseDecimal("1"), "")
+	assert.Error(t, err)
+	assert.Empty(t, l.Entries)
+}
@@ -0,0 +1,98 @@
+package currconv
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// maxLoggedBody is the maximum number of bytes of a response body logged on a decode failure.
+const maxLoggedBody = 512
+
+// LogLevels are the levels of the records logged to Config.Logger.
+// Set the level of the logger's handler above a level to turn its records off.
+type LogLevels struct {
+	// Request is the level of a successful request, default to slog.LevelInfo.
+	Request slog.Leveler
+	// Body is the level of the full response body of a successful request, default to slog.LevelDebug.
+	Body slog.Leveler
+	// Error is the level of a failed request, default to slog.LevelError.
+	Error slog.Leveler
+}
+
+// level returns `l`, or `fallback` if `l` is nil.
+func level(l slog.Leveler, fallback slog.Level) slog.Level {
+	if l == nil {
+		return fallback
+	}
+
+	return l.Level()
+}
+
+// log logs a finished request to Config.Logger, with the API key redacted.
+// `body` is the response body, only available if it was read.
+// Records have no retry attempt, API never retries a request, so each record is the only attempt of its request.
+func (a *API) log(info RequestInfo, body []byte) {
+	logger := a.config.Logger
+	if logger == nil {
+		return
+	}
+
+	levels := a.config.LogLevels
+	ctx := context.Background()
+
+	attrs := []slog.Attr{
+		slog.String("endpoint", info.Endpoint),
+		slog.String("url", info.URL),
+	}
+	if info.Pairs > 0 {
+		attrs = append(attrs, slog.Int("pairs", info.Pairs))
+	}
+	if info.Date != "" {
+		attrs = append(attrs, slog.String("date", info.Date))
+	}
+	if info.EndDate != "" {
+		attrs = append(attrs, slog.String("end_date", info.EndDate))
+	}
+	attrs = append(attrs, slog.Duration("duration", info.Duration))
+	if info.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", info.StatusCode))
+	}
+
+	if info.Err != nil {
+		attrs = append(attrs, slog.String("error", a.redact(info.Err.Error())))
+		if body != nil {
+			attrs = append(attrs, slog.String("body", truncate(string(body), maxLoggedBody)))
+		}
+		logger.LogAttrs(ctx, level(levels.Error, slog.LevelError), "currconv request failed", attrs...)
+		return
+	}
+
+	logger.LogAttrs(ctx, level(levels.Request, slog.LevelInfo), "currconv request", attrs...)
+
+	bodyLevel := level(levels.Body, slog.LevelDebug)
+	if body != nil && logger.Enabled(ctx, bodyLevel) {
+		logger.LogAttrs(ctx, bodyLevel, "currconv response",
+			slog.String("endpoint", info.Endpoint),
+			slog.String("body", string(body)),
+		)
+	}
+}
+
+// redact replaces the API key in `s`, e.g. in the URL of an error returned by http.Get.
+func (a *API) redact(s string) string {
+	if a.config.APIKey == "" {
+		return s
+	}
+
+	return strings.ReplaceAll(s, a.config.APIKey, redacted)
+}
+
+// truncate returns the first `n` bytes of `s`, followed by "..." if it is longer.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	return s[:n] + "..."
+}
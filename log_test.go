@@ -0,0 +1,222 @@
+package currconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// logRecords decodes the records written by a slog.JSONHandler.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		record := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestLogger(t *testing.T) {
+	invalid := `{"USD_MYR": ` + strings.Repeat(" ", 1000)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/convert":
+			if r.URL.Query().Get("date") != "" {
+				_, _ = w.Write([]byte(invalid))
+				return
+			}
+			_, _ = w.Write([]byte(`{"USD_MYR": 4.348493}`))
+		case "/api/v1/currencies":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status": 400, "error": "Invalid API key"}`))
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		level   slog.Level
+		levels  LogLevels
+		call    func(api *API) error
+		records []map[string]interface{}
+	}{
+		{
+			"Request",
+			slog.LevelInfo,
+			LogLevels{},
+			func(api *API) error {
+				_, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+				return err
+			},
+			[]map[string]interface{}{
+				{
+					"level":    "INFO",
+					"msg":      "currconv request",
+					"endpoint": "convert",
+					"url":      ts.URL + "/api/v1/convert?apiKey=REDACTED&compact=ultra&q=USD_MYR",
+					"pairs":    float64(1),
+					"status":   float64(200),
+				},
+			},
+		},
+		{
+			"Debug body",
+			slog.LevelDebug,
+			LogLevels{},
+			func(api *API) error {
+				_, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+				return err
+			},
+			[]map[string]interface{}{
+				{
+					"level":    "INFO",
+					"msg":      "currconv request",
+					"endpoint": "convert",
+					"url":      ts.URL + "/api/v1/convert?apiKey=REDACTED&compact=ultra&q=USD_MYR",
+					"pairs":    float64(1),
+					"status":   float64(200),
+				},
+				{
+					"level":    "DEBUG",
+					"msg":      "currconv response",
+					"endpoint": "convert",
+					"body":     `{"USD_MYR": 4.348493}`,
+				},
+			},
+		},
+		{
+			"Quiet",
+			slog.LevelWarn,
+			LogLevels{},
+			func(api *API) error {
+				_, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+				return err
+			},
+			nil,
+		},
+		{
+			"Custom level",
+			slog.LevelWarn,
+			LogLevels{Request: slog.LevelWarn},
+			func(api *API) error {
+				_, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+				return err
+			},
+			[]map[string]interface{}{
+				{
+					"level":    "WARN",
+					"msg":      "currconv request",
+					"endpoint": "convert",
+					"url":      ts.URL + "/api/v1/convert?apiKey=REDACTED&compact=ultra&q=USD_MYR",
+					"pairs":    float64(1),
+					"status":   float64(200),
+				},
+			},
+		},
+		{
+			"Error status",
+			slog.LevelWarn,
+			LogLevels{},
+			func(api *API) error {
+				_, err := api.Currencies()
+				return err
+			},
+			[]map[string]interface{}{
+				{
+					"level":    "ERROR",
+					"msg":      "currconv request failed",
+					"endpoint": "currencies",
+					"url":      ts.URL + "/api/v1/currencies?apiKey=REDACTED",
+					"status":   float64(400),
+					"error":    "Invalid API key",
+				},
+			},
+		},
+		{
+			"Decode failure",
+			slog.LevelWarn,
+			LogLevels{},
+			func(api *API) error {
+				date := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+				_, err := api.ConvertHistoricalCompact(ConvertHistoricalRequest{Q: []string{"USD_MYR"}, Date: date, EndDate: date.AddDate(0, 0, 1)})
+				return err
+			},
+			[]map[string]interface{}{
+				{
+					"level":    "ERROR",
+					"msg":      "currconv request failed",
+					"endpoint": "convert",
+					"url":      ts.URL + "/api/v1/convert?apiKey=REDACTED&compact=ultra&date=2023-02-01&endDate=2023-02-02&q=USD_MYR",
+					"pairs":    float64(1),
+					"date":     "2023-02-01",
+					"end_date": "2023-02-02",
+					"status":   float64(200),
+					"error":    "unexpected end of JSON input",
+					"body":     invalid[:maxLoggedBody] + "...",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			api := NewAPI(Config{
+				BaseURL:   ts.URL,
+				APIKey:    "secret",
+				Version:   "v1",
+				Logger:    slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: tt.level})),
+				LogLevels: tt.levels,
+			})
+
+			_ = tt.call(api)
+
+			assert.NotContains(t, buf.String(), "secret")
+
+			records := logRecords(t, buf)
+			assert.Len(t, records, len(tt.records))
+			for i, record := range records {
+				assert.NotEmpty(t, record["time"])
+				delete(record, "time")
+				if record["msg"] != "currconv response" {
+					assert.NotNil(t, record["duration"])
+					delete(record, "duration")
+				}
+				assert.Equal(t, tt.records[i], record)
+			}
+		})
+	}
+}
+
+func TestLogger_NetworkError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	api := NewAPI(Config{
+		BaseURL: "http://127.0.0.1:0/",
+		APIKey:  "secret",
+		Version: "v1",
+		Logger:  slog.New(slog.NewJSONHandler(buf, nil)),
+	})
+
+	_, err := api.Usage()
+	assert.ErrorContains(t, err, "secret")
+
+	records := logRecords(t, buf)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "currconv request failed", records[0]["msg"])
+	assert.Contains(t, records[0]["error"], "apiKey=REDACTED")
+	assert.NotContains(t, buf.String(), "secret")
+	assert.Nil(t, records[0]["status"])
+}
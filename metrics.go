@@ -0,0 +1,270 @@
+package currconv
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the default upper bounds in seconds of the request duration histogram, same as the Prometheus client.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// statusError is the status label of requests without a response, e.g. a network error.
+const statusError = "error"
+
+// Metrics collects metrics of API and serves them in the Prometheus text exposition format, without the Prometheus client.
+// Set it as Config.Metrics to collect requests and the rates of watched pairs.
+// Cache and rate limiter metrics are recorded by the components which own them, and stay at 0 otherwise.
+// The zero value is a Metrics without watched pairs.
+type Metrics struct {
+	// Buckets are the upper bounds in seconds of the request duration histogram, default to DefaultBuckets.
+	// Changing Buckets after the first request has no effect.
+	Buckets []float64
+
+	mu           sync.Mutex
+	requests     map[requestLabels]*histogram
+	cacheHits    uint64
+	cacheMisses  uint64
+	limiterWaits uint64
+	limiterWait  time.Duration
+	watched      map[string]bool
+	rates        map[string]float64
+}
+
+// requestLabels are the labels of request metrics.
+type requestLabels struct {
+	endpoint string
+	status   string
+}
+
+// histogram is a cumulative histogram of durations in seconds.
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewMetrics create and return a Metrics with the gauge of the last known rate of each of `pairs`,
+// in "[FROM]_[TO]" format.
+func NewMetrics(pairs ...string) *Metrics {
+	m := &Metrics{
+		requests: map[requestLabels]*histogram{},
+		watched:  map[string]bool{},
+		rates:    map[string]float64{},
+	}
+	m.Watch(pairs...)
+
+	return m
+}
+
+// Watch adds the gauge of the last known rate of each of `pairs`, updated by every current rate received.
+func (m *Metrics) Watch(pairs ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.initMaps()
+	for _, pair := range pairs {
+		m.watched[pair] = true
+	}
+}
+
+// ObserveCacheHit counts a rate served from a cache.
+func (m *Metrics) ObserveCacheHit() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cacheHits++
+}
+
+// ObserveCacheMiss counts a rate not found in a cache.
+func (m *Metrics) ObserveCacheMiss() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cacheMisses++
+}
+
+// ObserveLimiterWait counts a request delayed `d` by a rate limiter.
+func (m *Metrics) ObserveLimiterWait(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.limiterWaits++
+	m.limiterWait += d
+}
+
+// SetRate sets the last known rate of `pair`, if it is watched.
+func (m *Metrics) SetRate(pair string, rate float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.initMaps()
+	if m.watched[pair] {
+		m.rates[pair] = rate
+	}
+}
+
+// initMaps allocates the maps of a Metrics not created by NewMetrics. The caller must hold m.mu.
+func (m *Metrics) initMaps() {
+	if m.requests == nil {
+		m.requests = map[requestLabels]*histogram{}
+	}
+	if m.watched == nil {
+		m.watched = map[string]bool{}
+	}
+	if m.rates == nil {
+		m.rates = map[string]float64{}
+	}
+}
+
+// observeRequest records a finished request.
+func (m *Metrics) observeRequest(info RequestInfo) {
+	status := statusError
+	if info.StatusCode != 0 {
+		status = strconv.Itoa(info.StatusCode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.initMaps()
+	l := requestLabels{endpoint: info.Endpoint, status: status}
+	h, ok := m.requests[l]
+	if !ok {
+		bounds := m.Buckets
+		if len(bounds) == 0 {
+			bounds = DefaultBuckets
+		}
+		h = &histogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+		m.requests[l] = h
+	}
+
+	seconds := info.Duration.Seconds()
+	for i, b := range h.bounds {
+		if seconds <= b {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// observeResult sets the rates of watched pairs from a result of the convert endpoint.
+func (m *Metrics) observeResult(result interface{}) {
+	switch r := result.(type) {
+	case *Convert:
+		for pair, v := range r.Results {
+			m.SetRate(pair, float64(v.Val))
+		}
+	case *ConvertCompact:
+		for pair, v := range *r {
+			m.SetRate(pair, float64(v))
+		}
+	case *ConvertExact:
+		for pair, v := range r.Results {
+			m.SetRate(pair, v.Val.Float64())
+		}
+	case *ConvertCompactExact:
+		for pair, v := range *r {
+			m.SetRate(pair, v.Float64())
+		}
+	case *compactRates:
+		for pair, v := range *r {
+			if f, err := strconv.ParseFloat(string(v), 64); err == nil {
+				m.SetRate(pair, f)
+			}
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler, serving the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format to `w`.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	labels := make([]requestLabels, 0, len(m.requests))
+	for l := range m.requests {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].endpoint != labels[j].endpoint {
+			return labels[i].endpoint < labels[j].endpoint
+		}
+		return labels[i].status < labels[j].status
+	})
+
+	writeHeader(&b, "currconv_requests_total", "counter", "Requests sent to CurrencyConverterAPI.")
+	for _, l := range labels {
+		fmt.Fprintf(&b, "currconv_requests_total{endpoint=%s,status=%s} %d\n", quoteLabel(l.endpoint), quoteLabel(l.status), m.requests[l].count)
+	}
+
+	writeHeader(&b, "currconv_request_duration_seconds", "histogram", "Duration of requests sent to CurrencyConverterAPI.")
+	for _, l := range labels {
+		h := m.requests[l]
+		ls := fmt.Sprintf("endpoint=%s,status=%s", quoteLabel(l.endpoint), quoteLabel(l.status))
+		for i, bound := range h.bounds {
+			fmt.Fprintf(&b, "currconv_request_duration_seconds_bucket{%s,le=%s} %d\n", ls, quoteLabel(formatFloat(bound)), h.counts[i])
+		}
+		fmt.Fprintf(&b, "currconv_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", ls, h.count)
+		fmt.Fprintf(&b, "currconv_request_duration_seconds_sum{%s} %s\n", ls, formatFloat(h.sum))
+		fmt.Fprintf(&b, "currconv_request_duration_seconds_count{%s} %d\n", ls, h.count)
+	}
+
+	writeHeader(&b, "currconv_cache_hits_total", "counter", "Rates served from the cache.")
+	fmt.Fprintf(&b, "currconv_cache_hits_total %d\n", m.cacheHits)
+	writeHeader(&b, "currconv_cache_misses_total", "counter", "Rates not found in the cache.")
+	fmt.Fprintf(&b, "currconv_cache_misses_total %d\n", m.cacheMisses)
+
+	writeHeader(&b, "currconv_rate_limiter_waits_total", "counter", "Requests delayed by the rate limiter.")
+	fmt.Fprintf(&b, "currconv_rate_limiter_waits_total %d\n", m.limiterWaits)
+	writeHeader(&b, "currconv_rate_limiter_wait_seconds_total", "counter", "Time requests were delayed by the rate limiter.")
+	fmt.Fprintf(&b, "currconv_rate_limiter_wait_seconds_total %s\n", formatFloat(m.limiterWait.Seconds()))
+
+	writeHeader(&b, "currconv_rate", "gauge", "Last known rate of watched pairs.")
+	for _, pair := range sortedKeys(m.rates) {
+		fmt.Fprintf(&b, "currconv_rate{pair=%s} %s\n", quoteLabel(pair), formatFloat(m.rates[pair]))
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// writeHeader writes the HELP and TYPE lines of metric `name`.
+func writeHeader(b *strings.Builder, name string, typ string, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// quoteLabel quotes a label value, escaping backslashes, double quotes and line feeds.
+func quoteLabel(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+// formatFloat formats `f` in the shortest representation.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// sortedKeys returns the keys of `m`, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
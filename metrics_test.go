@@ -0,0 +1,131 @@
+package currconv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_WriteTo(t *testing.T) {
+	m := NewMetrics("USD_MYR")
+	m.Buckets = []float64{0.1, 1}
+
+	m.observeRequest(RequestInfo{Endpoint: EndpointConvert, StatusCode: 200, Duration: 50 * time.Millisecond})
+	m.observeRequest(RequestInfo{Endpoint: EndpointConvert, StatusCode: 200, Duration: 500 * time.Millisecond})
+	m.observeRequest(RequestInfo{Endpoint: EndpointConvert, StatusCode: 400, Duration: 2 * time.Second})
+	m.observeRequest(RequestInfo{Endpoint: "us\"age", Duration: time.Second})
+	m.ObserveCacheHit()
+	m.ObserveCacheHit()
+	m.ObserveCacheMiss()
+	m.ObserveLimiterWait(1500 * time.Millisecond)
+	m.SetRate("USD_MYR", 4.348493)
+	m.SetRate("MYR_USD", 0.229964)
+
+	var b strings.Builder
+	_, err := m.WriteTo(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP currconv_requests_total Requests sent to CurrencyConverterAPI.
+# TYPE currconv_requests_total counter
+currconv_requests_total{endpoint="convert",status="200"} 2
+currconv_requests_total{endpoint="convert",status="400"} 1
+currconv_requests_total{endpoint="us\"age",status="error"} 1
+# HELP currconv_request_duration_seconds Duration of requests sent to CurrencyConverterAPI.
+# TYPE currconv_request_duration_seconds histogram
+currconv_request_duration_seconds_bucket{endpoint="convert",status="200",le="0.1"} 1
+currconv_request_duration_seconds_bucket{endpoint="convert",status="200",le="1"} 2
+currconv_request_duration_seconds_bucket{endpoint="convert",status="200",le="+Inf"} 2
+currconv_request_duration_seconds_sum{endpoint="convert",status="200"} 0.55
+currconv_request_duration_seconds_count{endpoint="convert",status="200"} 2
+currconv_request_duration_seconds_bucket{endpoint="convert",status="400",le="0.1"} 0
+currconv_request_duration_seconds_bucket{endpoint="convert",status="400",le="1"} 0
+currconv_request_duration_seconds_bucket{endpoint="convert",status="400",le="+Inf"} 1
+currconv_request_duration_seconds_sum{endpoint="convert",status="400"} 2
+currconv_request_duration_seconds_count{endpoint="convert",status="400"} 1
+currconv_request_duration_seconds_bucket{endpoint="us\"age",status="error",le="0.1"} 0
+currconv_request_duration_seconds_bucket{endpoint="us\"age",status="error",le="1"} 1
+currconv_request_duration_seconds_bucket{endpoint="us\"age",status="error",le="+Inf"} 1
+currconv_request_duration_seconds_sum{endpoint="us\"age",status="error"} 1
+currconv_request_duration_seconds_count{endpoint="us\"age",status="error"} 1
+# HELP currconv_cache_hits_total Rates served from the cache.
+# TYPE currconv_cache_hits_total counter
+currconv_cache_hits_total 2
+# HELP currconv_cache_misses_total Rates not found in the cache.
+# TYPE currconv_cache_misses_total counter
+currconv_cache_misses_total 1
+# HELP currconv_rate_limiter_waits_total Requests delayed by the rate limiter.
+# TYPE currconv_rate_limiter_waits_total counter
+currconv_rate_limiter_waits_total 1
+# HELP currconv_rate_limiter_wait_seconds_total Time requests were delayed by the rate limiter.
+# TYPE currconv_rate_limiter_wait_seconds_total counter
+currconv_rate_limiter_wait_seconds_total 1.5
+# HELP currconv_rate Last known rate of watched pairs.
+# TYPE currconv_rate gauge
+currconv_rate{pair="USD_MYR"} 4.348493
+`, b.String())
+}
+
+func TestMetrics_API(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/currencies" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"USD_MYR": 4.348493, "MYR_USD": 0.229964}`))
+	}))
+	defer ts.Close()
+
+	m := NewMetrics("USD_MYR")
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+		Metrics: m,
+	})
+
+	_, err := api.ConvertCompactExact(ConvertRequest{Q: []string{"USD_MYR", "MYR_USD"}})
+	assert.NoError(t, err)
+	_, err = api.Currencies()
+	assert.Error(t, err)
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `currconv_requests_total{endpoint="convert",status="200"} 1`+"\n")
+	assert.Contains(t, w.Body.String(), `currconv_requests_total{endpoint="currencies",status="500"} 1`+"\n")
+	assert.Contains(t, w.Body.String(), `currconv_request_duration_seconds_count{endpoint="convert",status="200"} 1`+"\n")
+	assert.Contains(t, w.Body.String(), `currconv_rate{pair="USD_MYR"} 4.348493`+"\n")
+	assert.NotContains(t, w.Body.String(), `pair="MYR_USD"`)
+}
+
+func TestMetrics_Literal(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"USD_MYR": 4.348493}`))
+	}))
+	defer ts.Close()
+
+	m := &Metrics{}
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+		Metrics: m,
+	})
+
+	_, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+	assert.NoError(t, err)
+
+	m.Watch("USD_MYR")
+	_, err = api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+	assert.NoError(t, err)
+
+	var b strings.Builder
+	_, err = m.WriteTo(&b)
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), `currconv_requests_total{endpoint="convert",status="200"} 2`+"\n")
+	assert.Contains(t, b.String(), `currconv_rate{pair="USD_MYR"} 4.348493`+"\n")
+}
@@ -0,0 +1,256 @@
+package currconv
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrCurrencyMismatch is returned when an operation mixes Money of different currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// MinorUnits returns the number of digits after the decimal point of `currency` from the embedded dataset, default to 2.
+func MinorUnits(currency string) int32 {
+	if c, ok := FindISOCurrency(currency); ok {
+		return c.MinorUnits
+	}
+
+	return 2
+}
+
+// Money is an exact amount in a currency.
+// Operations between Money of different currencies fail with ErrCurrencyMismatch,
+// convert them first with a Converter.
+type Money struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// NewMoney create and return a Money.
+func NewMoney(amount Decimal, currency string) Money {
+	return Money{
+		Amount:   amount,
+		Currency: currency,
+	}
+}
+
+// Add returns m + n.
+func (m Money) Add(n Money) (Money, error) {
+	if m.Currency != n.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, n.Currency)
+	}
+
+	return Money{Amount: m.Amount.Add(n.Amount), Currency: m.Currency}, nil
+}
+
+// Sub returns m - n.
+func (m Money) Sub(n Money) (Money, error) {
+	return m.Add(n.Neg())
+}
+
+// Mul returns m * d, not rounded.
+func (m Money) Mul(d Decimal) Money {
+	return Money{Amount: m.Amount.Mul(d), Currency: m.Currency}
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return Money{Amount: m.Amount.Neg(), Currency: m.Currency}
+}
+
+// Round rounds m half away from zero to the minor units of its currency.
+func (m Money) Round() Money {
+	return Money{Amount: m.Amount.Round(MinorUnits(m.Currency)), Currency: m.Currency}
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of m.
+func (m Money) Sign() int {
+	return m.Amount.Sign()
+}
+
+// IsZero reports whether m is 0.
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// Cmp compares m and n and returns -1, 0 or +1.
+func (m Money) Cmp(n Money) (int, error) {
+	if m.Currency != n.Currency {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, n.Currency)
+	}
+
+	return m.Amount.Cmp(n.Amount), nil
+}
+
+// Equal reports whether m and n are the same amount in the same currency.
+func (m Money) Equal(n Money) bool {
+	return m.Currency == n.Currency && m.Amount.Equal(n.Amount)
+}
+
+// Allocate splits m, rounded to minor units, into parts proportional to `ratios`.
+// Parts always sum to the rounded m, the remaining minor units are given one by one from the first part.
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("`ratios` require at least one ratio")
+	}
+
+	sum := int64(0)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, errors.New("`ratios` must not be negative")
+		}
+		sum += int64(r)
+	}
+
+	if sum == 0 {
+		return nil, errors.New("`ratios` must not sum to zero")
+	}
+
+	places := MinorUnits(m.Currency)
+	total := m.Amount.Round(places).unscaled()
+
+	parts := make([]Money, len(ratios))
+	remainder := new(big.Int).Set(total)
+	for i, r := range ratios {
+		share := new(big.Int).Mul(total, big.NewInt(int64(r)))
+		share.Quo(share, big.NewInt(sum))
+		remainder.Sub(remainder, share)
+		parts[i] = Money{Amount: Decimal{coef: share, scale: places}, Currency: m.Currency}
+	}
+
+	unit := big.NewInt(int64(remainder.Sign()))
+	for i := 0; remainder.Sign() != 0; i++ {
+		if ratios[i%len(ratios)] == 0 {
+			continue
+		}
+		parts[i%len(ratios)].Amount.coef.Add(parts[i%len(ratios)].Amount.coef, unit)
+		remainder.Sub(remainder, unit)
+	}
+
+	return parts, nil
+}
+
+// Split splits m, rounded to minor units, into `n` equal parts without losing minor units.
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, errors.New("`n` must be positive")
+	}
+
+	ratios := make([]int, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+
+	return m.Allocate(ratios...)
+}
+
+// String returns m in "[CURRENCY] [AMOUNT]" format, e.g. "MYR 1234.50".
+func (m Money) String() string {
+	return m.Currency + " " + m.Amount.String()
+}
+
+// Value implements driver.Valuer, m is stored in "[CURRENCY] [AMOUNT]" format.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan implements sql.Scanner, reads a value written by Value.
+// A NULL value sets m to the zero Money, use *Money to tell NULL apart from a zero amount.
+func (m *Money) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*m = Money{}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	currency, amount, ok := strings.Cut(s, " ")
+	if !ok {
+		return fmt.Errorf("invalid money %q", s)
+	}
+
+	d, err := ParseDecimal(amount)
+	if err != nil {
+		return err
+	}
+
+	*m = Money{Amount: d, Currency: currency}
+	return nil
+}
+
+// Converter converts Money between currencies with rates from API.
+type Converter struct {
+	api *API
+}
+
+// NewConverter create and return a Converter.
+func NewConverter(api *API) *Converter {
+	return &Converter{
+		api,
+	}
+}
+
+// Convert converts `m` into `to` at the current rate from ConvertCompactExact, rounded to the minor units of `to`.
+func (c *Converter) Convert(m Money, to string) (Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+
+	pair := m.Currency + "_" + to
+	r, err := c.api.ConvertCompactExact(ConvertRequest{Q: []string{pair}})
+	if err != nil {
+		return Money{}, err
+	}
+
+	rate, ok := r[pair]
+	if !ok {
+		return Money{}, fmt.Errorf("%s: %w", pair, ErrRateNotFound)
+	}
+
+	return Money{Amount: m.Amount.Mul(rate), Currency: to}.Round(), nil
+}
+
+// Sum converts every Money into `to` and returns the sum.
+// Rates are fetched in as few requests as possible with ConvertMany.
+func (c *Converter) Sum(to string, ms ...Money) (Money, error) {
+	var q []string
+	seen := map[string]bool{}
+	for _, m := range ms {
+		if pair := m.Currency + "_" + to; m.Currency != to && !seen[pair] {
+			q = append(q, pair)
+			seen[pair] = true
+		}
+	}
+
+	rates := ConvertCompactExact{}
+	if len(q) > 0 {
+		var err error
+		rates, err = c.api.ConvertMany(ConvertRequest{Q: q})
+		if err != nil {
+			return Money{}, err
+		}
+	}
+
+	sum := Money{Currency: to}
+	for _, m := range ms {
+		if m.Currency != to {
+			rate, ok := rates[m.Currency+"_"+to]
+			if !ok {
+				return Money{}, fmt.Errorf("%s_%s: %w", m.Currency, to, ErrRateNotFound)
+			}
+			m = Money{Amount: m.Amount.Mul(rate), Currency: to}.Round()
+		}
+
+		sum.Amount = sum.Amount.Add(m.Amount)
+	}
+
+	return sum, nil
+}
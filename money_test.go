@@ -0,0 +1,204 @@
+package currconv
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinorUnits(t *testing.T) {
+	assert.Equal(t, int32(2), MinorUnits("MYR"))
+	assert.Equal(t, int32(0), MinorUnits("JPY"))
+	assert.Equal(t, int32(3), MinorUnits("KWD"))
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	myr := NewMoney(MustParseDecimal("10.50"), "MYR")
+
+	sum, err := myr.Add(NewMoney(MustParseDecimal("0.55"), "MYR"))
+	assert.NoError(t, err)
+	assert.Equal(t, "MYR 11.05", sum.String())
+
+	diff, err := myr.Sub(NewMoney(MustParseDecimal("20"), "MYR"))
+	assert.NoError(t, err)
+	assert.Equal(t, "MYR -9.50", diff.String())
+	assert.Equal(t, -1, diff.Sign())
+
+	_, err = myr.Add(NewMoney(MustParseDecimal("1"), "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	assert.EqualError(t, err, "currency mismatch: MYR and USD")
+
+	_, err = myr.Sub(NewMoney(MustParseDecimal("1"), "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	assert.Equal(t, "MYR 10.55250", myr.Mul(MustParseDecimal("1.005")).String())
+	assert.Equal(t, "MYR 10.55", myr.Mul(MustParseDecimal("1.005")).Round().String())
+	assert.Equal(t, "JPY 1235", NewMoney(MustParseDecimal("1234.5"), "JPY").Round().String())
+	assert.True(t, NewMoney(Decimal{}, "MYR").IsZero())
+}
+
+func TestMoney_Cmp(t *testing.T) {
+	a := NewMoney(MustParseDecimal("10.50"), "MYR")
+
+	c, err := a.Cmp(NewMoney(MustParseDecimal("10.5"), "MYR"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, c)
+
+	c, err = a.Cmp(NewMoney(MustParseDecimal("11"), "MYR"))
+	assert.NoError(t, err)
+	assert.Equal(t, -1, c)
+
+	_, err = a.Cmp(NewMoney(MustParseDecimal("10.50"), "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	assert.True(t, a.Equal(NewMoney(MustParseDecimal("10.5"), "MYR")))
+	assert.False(t, a.Equal(NewMoney(MustParseDecimal("10.50"), "USD")))
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		ratios   []int
+		expected []string
+		errorMsg string
+	}{
+		{"Even", NewMoney(MustParseDecimal("100"), "MYR"), []int{1, 1}, []string{"50.00", "50.00"}, ""},
+		{"Remainder", NewMoney(MustParseDecimal("100"), "MYR"), []int{1, 1, 1}, []string{"33.34", "33.33", "33.33"}, ""},
+		{"Ratios", NewMoney(MustParseDecimal("0.05"), "USD"), []int{3, 7}, []string{"0.02", "0.03"}, ""},
+		{"Negative", NewMoney(MustParseDecimal("-100"), "MYR"), []int{1, 1, 1}, []string{"-33.34", "-33.33", "-33.33"}, ""},
+		{"Zero ratio", NewMoney(MustParseDecimal("10"), "JPY"), []int{0, 1, 1}, []string{"0", "5", "5"}, ""},
+		{"No minor units", NewMoney(MustParseDecimal("100"), "JPY"), []int{1, 1, 1}, []string{"34", "33", "33"}, ""},
+		{"No ratios", NewMoney(MustParseDecimal("100"), "MYR"), nil, nil, "`ratios` require at least one ratio"},
+		{"Negative ratio", NewMoney(MustParseDecimal("100"), "MYR"), []int{1, -1}, nil, "`ratios` must not be negative"},
+		{"Zero ratios", NewMoney(MustParseDecimal("100"), "MYR"), []int{0, 0}, nil, "`ratios` must not sum to zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := tt.money.Allocate(tt.ratios...)
+			if tt.errorMsg != "" {
+				assert.EqualError(t, err, tt.errorMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			amounts := make([]string, len(parts))
+			sum := Money{Currency: tt.money.Currency}
+			for i, p := range parts {
+				amounts[i] = p.Amount.String()
+				sum, _ = sum.Add(p)
+			}
+			assert.Equal(t, tt.expected, amounts)
+			assert.True(t, sum.Equal(tt.money.Round()))
+		})
+	}
+
+	parts, err := NewMoney(MustParseDecimal("10"), "MYR").Split(3)
+	assert.NoError(t, err)
+	assert.Equal(t, "MYR 3.34", parts[0].String())
+
+	_, err = NewMoney(MustParseDecimal("10"), "MYR").Split(0)
+	assert.EqualError(t, err, "`n` must be positive")
+}
+
+func TestMoney_JSON(t *testing.T) {
+	b, err := json.Marshal(NewMoney(MustParseDecimal("1234.50"), "MYR"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount": 1234.50, "currency": "MYR"}`, string(b))
+
+	m := Money{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount": "99.90", "currency": "USD"}`), &m))
+	assert.Equal(t, "USD 99.90", m.String())
+}
+
+func TestMoney_SQL(t *testing.T) {
+	v, err := NewMoney(MustParseDecimal("1234.50"), "MYR").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "MYR 1234.50", v)
+
+	tests := []struct {
+		name     string
+		src      interface{}
+		expected string
+		errorMsg string
+	}{
+		{"String", "MYR 1234.50", "MYR 1234.50", ""},
+		{"Bytes", []byte("JPY 1235"), "JPY 1235", ""},
+		{"Unsupported type", 1, "", "cannot scan int into Money"},
+		{"Missing amount", "MYR", "", "invalid money \"MYR\""},
+		{"Invalid amount", "MYR abc", "", "invalid decimal \"abc\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Money{}
+			err := m.Scan(tt.src)
+			if tt.errorMsg != "" {
+				assert.EqualError(t, err, tt.errorMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, m.String())
+		})
+	}
+
+	m := NewMoney(MustParseDecimal("1234.50"), "MYR")
+	assert.NoError(t, m.Scan(nil))
+	assert.Equal(t, Money{}, m, "NULL scans into the zero Money")
+}
+
+func TestConverter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rates := map[string]string{"USD_MYR": "4.348493", "SGD_MYR": "3.2711", "USD_JPY": "134.12"}
+
+		result := map[string]json.RawMessage{}
+		for _, pair := range strings.Split(r.URL.Query().Get("q"), ",") {
+			if rate, ok := rates[pair]; ok {
+				result[pair] = json.RawMessage(rate)
+			}
+		}
+
+		b, _ := json.Marshal(result)
+		_, _ = w.Write(b)
+	}))
+	defer ts.Close()
+
+	converter := NewConverter(NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	}))
+
+	m, err := converter.Convert(NewMoney(MustParseDecimal("10"), "USD"), "MYR")
+	assert.NoError(t, err)
+	assert.Equal(t, "MYR 43.48", m.String())
+
+	m, err = converter.Convert(NewMoney(MustParseDecimal("10.55"), "USD"), "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, "JPY 1415", m.String())
+
+	m, err = converter.Convert(NewMoney(MustParseDecimal("10"), "MYR"), "MYR")
+	assert.NoError(t, err)
+	assert.Equal(t, "MYR 10", m.String())
+
+	_, err = converter.Convert(NewMoney(MustParseDecimal("10"), "EUR"), "MYR")
+	assert.ErrorIs(t, err, ErrRateNotFound)
+
+	sum, err := converter.Sum("MYR",
+		NewMoney(MustParseDecimal("10"), "USD"),
+		NewMoney(MustParseDecimal("10"), "SGD"),
+		NewMoney(MustParseDecimal("1.50"), "MYR"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "MYR 77.69", sum.String())
+
+	_, err = converter.Sum("MYR", NewMoney(MustParseDecimal("10"), "EUR"))
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
@@ -0,0 +1,298 @@
+package currconv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// preferredSymbolCurrencies are the currencies picked for a symbol shared by multiple currencies, without a country hint.
+var preferredSymbolCurrencies = map[string]string{
+	"$": "USD", "¥": "JPY", "£": "GBP", "kr": "SEK", "R": "ZAR",
+}
+
+// multipliers are the suffixes of abbreviated amounts, e.g. "1.2k".
+var multipliers = map[string]int64{
+	"k": 1e3, "m": 1e6, "mn": 1e6, "b": 1e9, "bn": 1e9,
+}
+
+// ParseResult is the result of Parser.Parse.
+type ParseResult struct {
+	Money Money
+	// Ambiguous reports whether the currency text matched more than one currency.
+	Ambiguous bool
+	// Candidates are all currencies matched by the currency text, sorted.
+	Candidates []string
+}
+
+// Parser parses human-entered money strings, e.g. "RM 1,234.50", "$1.2k", "1.234,50 EUR" or "¥5000".
+type Parser struct {
+	// Locale decides the decimal separator of numbers like "1.234", e.g. "de-DE" parses it as 1234.
+	Locale string
+	// Country is the preferred ISO 3166-1 alpha-2 code to pick a currency of an ambiguous symbol, e.g. "$" is AUD in "AU".
+	Country string
+
+	symbols   map[string][]string
+	names     map[string]string
+	countries map[string]string
+	codes     map[string]bool
+}
+
+// NewParser create and return a Parser with symbols and names from the results of Currencies and Countries.
+// Both results are optional, the embedded symbols, and names and countries of the embedded dataset are always used.
+func NewParser(currencies *Currency, countries *Country) *Parser {
+	p := &Parser{
+		symbols:   map[string][]string{},
+		names:     map[string]string{},
+		countries: map[string]string{},
+		codes:     map[string]bool{},
+	}
+
+	for code, symbol := range symbols {
+		p.addSymbol(symbol, code)
+	}
+
+	for code, c := range DatasetCurrencies().Results {
+		p.codes[code] = true
+		p.addSymbol(c.CurrencySymbol, code)
+		p.addName(c.CurrencyName, code)
+	}
+
+	if currencies != nil {
+		for code, c := range currencies.Results {
+			p.codes[code] = true
+			p.addSymbol(c.CurrencySymbol, code)
+			p.addName(c.CurrencyName, code)
+		}
+	}
+
+	for id, c := range DatasetCountries().Results {
+		p.countries[id] = c.CurrencyID
+	}
+
+	if countries != nil {
+		for id, c := range countries.Results {
+			p.codes[c.CurrencyID] = true
+			p.addSymbol(c.CurrencySymbol, c.CurrencyID)
+			p.addName(c.CurrencyName, c.CurrencyID)
+			p.countries[id] = c.CurrencyID
+		}
+	}
+
+	for symbol := range p.symbols {
+		sort.Strings(p.symbols[symbol])
+	}
+
+	return p
+}
+
+// ParseMoney parses `s` with the embedded symbols, see Parser.Parse.
+func ParseMoney(s string) (Money, error) {
+	r, err := NewParser(nil, nil).Parse(s)
+	return r.Money, err
+}
+
+// Parse parses `s` into Money.
+// The currency could be an ISO 4217 code, a symbol or a currency name, before or after the number.
+// The number could have group separators, a decimal separator, and a "k", "m" or "b" suffix.
+func (p *Parser) Parse(s string) (ParseResult, error) {
+	invalid := fmt.Errorf("invalid money %q", s)
+
+	first := strings.IndexFunc(s, unicode.IsDigit)
+	last := strings.LastIndexFunc(s, unicode.IsDigit)
+	if first < 0 {
+		return ParseResult{}, invalid
+	}
+
+	prefix, number, suffix := s[:first], s[first:last+1], s[last+1:]
+
+	negative := false
+	if t := strings.TrimSpace(prefix); strings.HasPrefix(t, "-") || strings.HasSuffix(t, "-") {
+		negative = true
+		prefix = strings.Replace(prefix, "-", "", 1)
+	}
+
+	multiplier := int64(1)
+	word := strings.TrimLeftFunc(suffix, unicode.IsSpace)
+	if end := strings.IndexFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }); end >= 0 {
+		word = word[:end]
+	}
+	if m, ok := multipliers[strings.ToLower(word)]; ok && word != "" {
+		multiplier, suffix = m, strings.TrimLeftFunc(suffix, unicode.IsSpace)[len(word):]
+	}
+
+	currencyText := strings.TrimSpace(prefix)
+	if t := strings.TrimSpace(suffix); t != "" {
+		if currencyText != "" {
+			return ParseResult{}, invalid
+		}
+		currencyText = t
+	}
+
+	if currencyText == "" {
+		return ParseResult{}, fmt.Errorf("currency is missing in %q", s)
+	}
+
+	amount, err := p.parseNumber(number)
+	if err != nil {
+		return ParseResult{}, invalid
+	}
+
+	amount = amount.Mul(NewDecimalFromInt(multiplier))
+	if negative {
+		amount = amount.Neg()
+	}
+
+	candidates := p.currencies(currencyText)
+	if len(candidates) == 0 {
+		return ParseResult{}, fmt.Errorf("unknown currency %q", currencyText)
+	}
+
+	return ParseResult{
+		Money:      Money{Amount: amount, Currency: p.pick(currencyText, candidates)},
+		Ambiguous:  len(candidates) > 1,
+		Candidates: candidates,
+	}, nil
+}
+
+// currencies returns the currencies matched by `text`.
+// A code must be in the embedded dataset or in the results of Currencies and Countries.
+func (p *Parser) currencies(text string) []string {
+	if p.codes[text] {
+		return []string{text}
+	}
+
+	if codes, ok := p.symbols[text]; ok {
+		return codes
+	}
+
+	lower := strings.ToLower(text)
+	for _, name := range []string{lower, strings.TrimSuffix(lower, "s")} {
+		if code, ok := p.names[name]; ok {
+			return []string{code}
+		}
+	}
+
+	if upper := strings.ToUpper(text); p.codes[upper] {
+		return []string{upper}
+	}
+
+	return nil
+}
+
+// pick picks a currency of `candidates`, by the country hint first and then by preferredSymbolCurrencies.
+func (p *Parser) pick(text string, candidates []string) string {
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	preferred := []string{p.countries[strings.ToUpper(p.Country)], preferredSymbolCurrencies[text]}
+	for _, code := range preferred {
+		for _, c := range candidates {
+			if c == code {
+				return c
+			}
+		}
+	}
+
+	return candidates[0]
+}
+
+// parseNumber parses `s` with group and decimal separators into a Decimal.
+func (p *Parser) parseNumber(s string) (Decimal, error) {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '\'' || r == '’' {
+			return -1
+		}
+		return r
+	}, s)
+
+	dot, comma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+
+	decimal := ""
+	switch {
+	case dot >= 0 && comma >= 0:
+		decimal = "."
+		if comma > dot {
+			decimal = ","
+		}
+	case dot >= 0:
+		decimal = p.decimalSeparator(s, ".")
+	case comma >= 0:
+		decimal = p.decimalSeparator(s, ",")
+	}
+
+	intPart, fracPart := s, ""
+	if decimal != "" {
+		i := strings.LastIndex(s, decimal)
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+
+	// Groups after the first one have 3 digits, or 2 digits for secondary grouping like 12,34,567.
+	groups := strings.FieldsFunc(intPart, func(r rune) bool { return r == '.' || r == ',' })
+	for i, g := range groups {
+		if i > 0 && len(g) != 3 && (len(g) != 2 || i == len(groups)-1) {
+			return Decimal{}, fmt.Errorf("invalid number %q", s)
+		}
+	}
+
+	if decimal == "" {
+		return ParseDecimal(strings.Join(groups, ""))
+	}
+
+	return ParseDecimal(strings.Join(groups, "") + "." + fracPart)
+}
+
+// decimalSeparator returns `sep` if it is the decimal separator of `s`, or an empty string if it is the group separator.
+func (p *Parser) decimalSeparator(s string, sep string) string {
+	if strings.Count(s, sep) > 1 {
+		return ""
+	}
+
+	if len(s)-strings.Index(s, sep)-1 != 3 {
+		return sep
+	}
+
+	// Such as "1.234", depends on the locale.
+	if FindLocale(p.Locale).Group == sep {
+		return ""
+	}
+
+	return sep
+}
+
+// addSymbol maps `symbol` to `code`.
+func (p *Parser) addSymbol(symbol string, code string) {
+	if symbol == "" || code == "" {
+		return
+	}
+
+	for _, c := range p.symbols[symbol] {
+		if c == code {
+			return
+		}
+	}
+
+	p.symbols[symbol] = append(p.symbols[symbol], code)
+}
+
+// addName maps the lower case `name` to `code`.
+func (p *Parser) addName(name string, code string) {
+	if name == "" || code == "" {
+		return
+	}
+
+	p.names[strings.ToLower(name)] = code
+}
+
+// isLetters reports whether `s` only contains ASCII letters.
+func isLetters(s string) bool {
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
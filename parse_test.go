@@ -0,0 +1,108 @@
+package currconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Parse(t *testing.T) {
+	// dollars are the currencies with "$" as embedded or dataset symbol.
+	dollars := []string{"ARS", "AUD", "BBD", "BMD", "BND", "BSD", "CAD", "CLP", "COP", "CVE", "FJD", "GYD", "HKD", "KYD",
+		"LRD", "MXN", "NAD", "NZD", "SBD", "SGD", "SRD", "USD", "XCD", "ZWL"}
+
+	currencies := &Currency{
+		Results: map[string]CurrencyInfo{
+			"MYR": {ID: "MYR", CurrencyName: "Malaysian Ringgit", CurrencySymbol: "RM"},
+			"USD": {ID: "USD", CurrencyName: "United States Dollar", CurrencySymbol: "$"},
+			"AUD": {ID: "AUD", CurrencyName: "Australian Dollar", CurrencySymbol: "$"},
+			"EUR": {ID: "EUR", CurrencyName: "Euro", CurrencySymbol: "€"},
+		},
+	}
+	countries := &Country{
+		Results: map[string]CountryInfo{
+			"AU": {ID: "AU", Alpha3: "AUS", CurrencyID: "AUD", CurrencyName: "Australian dollar", CurrencySymbol: "$", Name: "Australia"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		input      string
+		locale     string
+		country    string
+		expected   string
+		ambiguous  bool
+		candidates []string
+		errorMsg   string
+	}{
+		{"Symbol with space", "RM 1,234.50", "", "", "MYR 1234.50", false, []string{"MYR"}, ""},
+		{"Abbreviated", "$1.2k", "", "", "USD 1200.0", true, dollars, ""},
+		{"European", "1.234,50 EUR", "", "", "EUR 1234.50", false, []string{"EUR"}, ""},
+		{"Yen", "¥5000", "", "", "JPY 5000", true, []string{"CNY", "JPY"}, ""},
+		{"Country hint", "$ 20", "", "AU", "AUD 20", true, dollars, ""},
+		{"Country hint not matched", "$ 20", "", "MY", "USD 20", true, dollars, ""},
+		{"Locale group", "€1.234", "de-DE", "", "EUR 1234", false, []string{"EUR"}, ""},
+		{"Locale decimal", "€1.234", "en-US", "", "EUR 1.234", false, []string{"EUR"}, ""},
+		{"Comma decimal", "1,5 €", "", "", "EUR 1.5", false, []string{"EUR"}, ""},
+		{"Multiple groups", "1,234,567 USD", "", "", "USD 1234567", false, []string{"USD"}, ""},
+		{"Swiss groups", "CHF 1'234.50", "", "", "CHF 1234.50", false, []string{"CHF"}, ""},
+		{"Space groups", "1 234 567,89 €", "fr-FR", "", "EUR 1234567.89", false, []string{"EUR"}, ""},
+		{"Negative", "-$5", "", "", "USD -5", true, dollars, ""},
+		{"Dataset symbol", "KD 5", "", "", "KWD 5", false, []string{"KWD"}, ""},
+		{"Negative after symbol", "RM -5.50", "", "", "MYR -5.50", false, []string{"MYR"}, ""},
+		{"Million", "2.5m MYR", "", "", "MYR 2500000.0", false, []string{"MYR"}, ""},
+		{"Name", "10 euros", "", "", "EUR 10", false, []string{"EUR"}, ""},
+		{"Lower case code", "10 myr", "", "", "MYR 10", false, []string{"MYR"}, ""},
+		{"No number", "RM", "", "", "", false, nil, "invalid money \"RM\""},
+		{"No currency", "1,234.50", "", "", "", false, nil, "currency is missing in \"1,234.50\""},
+		{"Unknown currency", "10 abcd", "", "", "", false, nil, "unknown currency \"abcd\""},
+		{"Unknown code", "10 xyz", "", "", "", false, nil, "unknown currency \"xyz\""},
+		{"Unknown upper case code", "ABC 10", "", "", "", false, nil, "unknown currency \"ABC\""},
+		{"Lower case code", "10 myr", "", "", "MYR 10", false, []string{"MYR"}, ""},
+		{"Currency twice", "$10 USD", "", "", "", false, nil, "invalid money \"$10 USD\""},
+		{"Invalid number", "$1.2.3,4,5", "", "", "", false, nil, "invalid money \"$1.2.3,4,5\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser(currencies, countries)
+			p.Locale = tt.locale
+			p.Country = tt.country
+
+			r, err := p.Parse(tt.input)
+			if tt.errorMsg != "" {
+				assert.EqualError(t, err, tt.errorMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, r.Money.String())
+			assert.Equal(t, tt.ambiguous, r.Ambiguous)
+			assert.Equal(t, tt.candidates, r.Candidates)
+		})
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	m, err := ParseMoney("RM 1,234.50")
+	assert.NoError(t, err)
+	assert.Equal(t, "MYR 1234.50", m.String())
+
+	m, err = ParseMoney("KD 5")
+	assert.NoError(t, err)
+	assert.Equal(t, "KWD 5", m.String())
+
+	_, err = ParseMoney("10 abc")
+	assert.EqualError(t, err, "unknown currency \"abc\"")
+
+	_, err = ParseMoney("abc")
+	assert.EqualError(t, err, "invalid money \"abc\"")
+}
+
+func TestParser_SuppliedCodes(t *testing.T) {
+	p := NewParser(&Currency{Results: map[string]CurrencyInfo{"XBT": {ID: "XBT", CurrencyName: "Bitcoin"}}}, nil)
+
+	r, err := p.Parse("0.5 XBT")
+	assert.NoError(t, err)
+	assert.Equal(t, "XBT 0.5", r.Money.String(), "a code from Currencies is known")
+}
@@ -0,0 +1,196 @@
+package currconv
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultPreloadInterval = 30 * time.Minute
+
+// Preloader fetches the rates of a configured list of pairs ahead of use, and keeps refreshing them on a schedule.
+// Rates are fetched with ConvertCompact in chunks within MaxPairsPerRequest, bypassing Config.Cache but storing to it,
+// so that ConvertCompact, ConvertCompactExact and ConvertMany serve the preloaded pairs from the cache.
+// Create it with NewPreloader, Preload of a Preloader without API fails.
+type Preloader struct {
+	// Pairs are the pairs to preload, in "[FROM]_[TO]" format.
+	Pairs []string
+	// Interval is the duration between two scheduled refreshes, default to 30 minutes.
+	Interval time.Duration
+	// OnError runs when a scheduled refresh fails. It could be nil.
+	OnError func(err error)
+
+	api       *API
+	mu        sync.Mutex
+	refreshed map[string]time.Time
+	stop      chan struct{}
+	done      chan struct{}
+	now       func() time.Time
+}
+
+// NewPreloader create and return a Preloader of `pairs` fetching with `api`.
+func NewPreloader(api *API, pairs ...string) *Preloader {
+	return &Preloader{
+		Pairs:     pairs,
+		api:       api,
+		refreshed: map[string]time.Time{},
+		now:       time.Now,
+	}
+}
+
+// Preload fetches the rates of all pairs once. It tries every chunk and returns the first error, if any.
+// A pair missing in the result of CurrencyConverterAPI returns ErrRateNotFound. It does nothing without pairs.
+func (p *Preloader) Preload() error {
+	if len(p.Pairs) == 0 {
+		return nil
+	}
+
+	if p.api == nil {
+		return errors.New("Preloader requires an API, create it with NewPreloader")
+	}
+
+	a := *p.api
+	a.refreshCache = true
+
+	var first error
+	for _, q := range chunk(p.Pairs, a.maxPairsPerRequest()) {
+		r, err := a.ConvertCompact(ConvertRequest{Q: q})
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+
+		now := p.clock()
+		p.mu.Lock()
+		if p.refreshed == nil {
+			p.refreshed = map[string]time.Time{}
+		}
+		for _, pair := range q {
+			if _, ok := r[pair]; ok {
+				p.refreshed[pair] = now
+			} else if first == nil {
+				first = fmt.Errorf("%s: %w", pair, ErrRateNotFound)
+			}
+		}
+		p.mu.Unlock()
+	}
+
+	return first
+}
+
+// clock returns the current time, from `now` if it is set.
+func (p *Preloader) clock() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+
+	return time.Now()
+}
+
+// Start refreshes all pairs every Interval in the background, starting immediately if the Preloader is not ready.
+// It does nothing if the Preloader is already started.
+func (p *Preloader) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		return
+	}
+
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.run(p.stop, p.done, !p.readyLocked())
+}
+
+// Stop stops the scheduled refresh started by Start, waiting for a refresh in progress.
+func (p *Preloader) Stop() {
+	p.mu.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	p.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+}
+
+// run refreshes all pairs every Interval until `stop` is closed, then closes `done`.
+func (p *Preloader) run(stop, done chan struct{}, immediately bool) {
+	defer close(done)
+
+	interval := p.Interval
+	if interval <= 0 {
+		interval = defaultPreloadInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	if immediately {
+		p.refresh()
+	}
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.refresh()
+		}
+	}
+}
+
+// refresh runs Preload, reporting its error to OnError.
+func (p *Preloader) refresh() {
+	if err := p.Preload(); err != nil && p.OnError != nil {
+		p.OnError(err)
+	}
+}
+
+// Ready reports whether every pair has been fetched at least once, e.g. for a readiness probe.
+func (p *Preloader) Ready() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.readyLocked()
+}
+
+// readyLocked is Ready with p.mu held.
+func (p *Preloader) readyLocked() bool {
+	for _, pair := range p.Pairs {
+		if _, ok := p.refreshed[pair]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RefreshedAt returns the last time the rate of `pair` was fetched.
+// The second return value reports whether it has been fetched at all.
+func (p *Preloader) RefreshedAt(pair string) (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t, ok := p.refreshed[pair]
+	return t, ok
+}
+
+// Refreshed returns the last time the rate of each fetched pair was fetched.
+func (p *Preloader) Refreshed() map[string]time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	refreshed := make(map[string]time.Time, len(p.refreshed))
+	for pair, t := range p.refreshed {
+		refreshed[pair] = t
+	}
+
+	return refreshed
+}
@@ -0,0 +1,141 @@
+package currconv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreloader(t *testing.T) {
+	rs := &rateServer{rates: map[string]string{"USD_MYR": "4.348493", "EUR_MYR": "4.6809", "SGD_MYR": "3.2786"}}
+	ts := httptest.NewServer(rs)
+	defer ts.Close()
+
+	clock := &testClock{now: time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)}
+	cache := NewRateCache()
+	cache.now = clock.Now
+
+	api := NewAPI(Config{
+		BaseURL:            ts.URL,
+		APIKey:             "secret",
+		Version:            "v1",
+		MaxPairsPerRequest: 2,
+		Cache:              cache,
+	})
+
+	p := NewPreloader(api, "USD_MYR", "EUR_MYR", "SGD_MYR")
+	p.now = clock.Now
+	assert.False(t, p.Ready())
+
+	assert.NoError(t, p.Preload())
+	assert.True(t, p.Ready())
+	assert.Equal(t, []string{"USD_MYR,EUR_MYR", "SGD_MYR"}, rs.requests())
+
+	refreshedAt, ok := p.RefreshedAt("SGD_MYR")
+	assert.True(t, ok)
+	assert.Equal(t, clock.Now(), refreshedAt)
+	_, ok = p.RefreshedAt("GBP_MYR")
+	assert.False(t, ok)
+
+	r, err := api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR", "SGD_MYR"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ConvertCompact{"USD_MYR": 4.348493, "SGD_MYR": 3.2786}, r)
+	assert.Len(t, rs.requests(), 2, "preloaded pairs are served from the cache")
+
+	clock.Add(time.Minute)
+	rs.set("USD_MYR", "4.4")
+	assert.NoError(t, p.Preload())
+	assert.Len(t, rs.requests(), 4, "a preload bypasses the cache")
+	assert.Equal(t, map[string]time.Time{
+		"USD_MYR": clock.Now(),
+		"EUR_MYR": clock.Now(),
+		"SGD_MYR": clock.Now(),
+	}, p.Refreshed())
+
+	r, err = api.ConvertCompact(ConvertRequest{Q: []string{"USD_MYR"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ConvertCompact{"USD_MYR": 4.4}, r)
+}
+
+func TestPreloader_Literal(t *testing.T) {
+	rs := &rateServer{rates: map[string]string{"USD_MYR": "4.348493"}}
+	ts := httptest.NewServer(rs)
+	defer ts.Close()
+
+	assert.NoError(t, NewPreloader(nil).Preload(), "nothing is fetched without pairs")
+	assert.True(t, NewPreloader(nil).Ready())
+
+	p := &Preloader{Pairs: []string{"USD_MYR"}}
+	assert.EqualError(t, p.Preload(), "Preloader requires an API, create it with NewPreloader")
+
+	p = NewPreloader(NewAPI(Config{BaseURL: ts.URL, APIKey: "secret", Version: "v1"}), "USD_MYR")
+	p.now = nil
+	p.refreshed = nil
+	assert.NoError(t, p.Preload())
+	assert.True(t, p.Ready())
+
+	_, ok := p.RefreshedAt("USD_MYR")
+	assert.True(t, ok)
+}
+
+func TestPreloader_NotFound(t *testing.T) {
+	rs := &rateServer{rates: map[string]string{"USD_MYR": "4.348493"}}
+	ts := httptest.NewServer(rs)
+	defer ts.Close()
+
+	api := NewAPI(Config{BaseURL: ts.URL, APIKey: "secret", Version: "v1"})
+
+	p := NewPreloader(api, "USD_MYR", "XXX_MYR")
+	err := p.Preload()
+	assert.ErrorIs(t, err, ErrRateNotFound)
+	assert.EqualError(t, err, "XXX_MYR: rate not found")
+	assert.False(t, p.Ready())
+
+	_, ok := p.RefreshedAt("USD_MYR")
+	assert.True(t, ok)
+}
+
+func TestPreloader_Start(t *testing.T) {
+	var mu sync.Mutex
+	fail := false
+	rs := &rateServer{rates: map[string]string{"USD_MYR": "4.348493"}}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status": 503, "error": "Service Unavailable"}`))
+			return
+		}
+		rs.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{BaseURL: ts.URL, APIKey: "secret", Version: "v1"})
+
+	errs := make(chan error, 100)
+	p := NewPreloader(api, "USD_MYR")
+	p.Interval = 5 * time.Millisecond
+	p.OnError = func(err error) { errs <- err }
+
+	p.Start()
+	p.Start()
+	assert.Eventually(t, p.Ready, time.Second, time.Millisecond, "a started Preloader preloads immediately")
+	assert.Eventually(t, func() bool { return len(rs.requests()) >= 3 }, time.Second, time.Millisecond, "and refreshes on schedule")
+
+	mu.Lock()
+	fail = true
+	mu.Unlock()
+	assert.EqualError(t, <-errs, "Service Unavailable")
+	assert.True(t, p.Ready(), "a failed refresh keeps the Preloader ready")
+
+	p.Stop()
+	p.Stop()
+	n := len(errs)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, n, len(errs), "a stopped Preloader does not refresh")
+}
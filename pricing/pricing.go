@@ -0,0 +1,195 @@
+// Package pricing localizes catalog prices into market currencies with currency-specific rounding and charm pricing.
+package pricing
+
+import (
+	"errors"
+	"fmt"
+
+	currconv "github.com/kitloong/go-currency-converter-api/v2"
+)
+
+// Rounding is the direction a price is rounded to the nearest price allowed by a Rule.
+type Rounding int
+
+const (
+	// RoundUp rounds to the smallest allowed price not below the converted price.
+	RoundUp Rounding = iota
+	// RoundNearest rounds to the nearest allowed price, halves are rounded up.
+	RoundNearest
+	// RoundDown rounds to the largest allowed price not above the converted price, or up if that is not positive.
+	RoundDown
+)
+
+// Rule is how prices are localized in a currency.
+// Allowed prices are the multiples of Step plus Ending, e.g. 0.99, 1.99, 2.99 with a Step of 1 and an Ending of 0.99.
+type Rule struct {
+	// Step is the distance between allowed prices, e.g. 100 for whole hundreds, default to the minor unit of the currency.
+	Step currconv.Decimal
+	// Ending is the charm ending of allowed prices, e.g. 0.99 or 0.90.
+	Ending currconv.Decimal
+	// Rounding is the direction prices are rounded, default to RoundUp.
+	Rounding Rounding
+	// Min and Max bound final prices, zero means unbounded.
+	Min currconv.Decimal
+	Max currconv.Decimal
+}
+
+// DefaultRules are the rules of New, a currency without a rule is rounded up to its minor units.
+var DefaultRules = map[string]Rule{
+	"USD": {Step: currconv.NewDecimalFromInt(1), Ending: currconv.MustParseDecimal("0.99")},
+	"EUR": {Step: currconv.NewDecimalFromInt(1), Ending: currconv.MustParseDecimal("0.99")},
+	"JPY": {Step: currconv.NewDecimalFromInt(100)},
+	"CHF": {Step: currconv.NewDecimalFromInt(1), Ending: currconv.MustParseDecimal("0.90")},
+}
+
+// Apply applies the rule to `m`, the result is rounded to the minor units of its currency.
+func (r Rule) Apply(m currconv.Money) currconv.Money {
+	places := currconv.MinorUnits(m.Currency)
+
+	step := r.Step
+	if step.Sign() <= 0 {
+		step = currconv.MustParseDecimal(fmt.Sprintf("1e-%d", places))
+	}
+
+	// lower is the largest allowed price not above the amount, and upper the smallest not below.
+	lower := m.Amount.Sub(r.Ending).Div(step, 0).Mul(step).Add(r.Ending)
+	for lower.Cmp(m.Amount) > 0 {
+		lower = lower.Sub(step)
+	}
+	for lower.Add(step).Cmp(m.Amount) <= 0 {
+		lower = lower.Add(step)
+	}
+
+	upper := lower
+	if lower.Cmp(m.Amount) < 0 {
+		upper = lower.Add(step)
+	}
+
+	price := upper
+	switch r.Rounding {
+	case RoundNearest:
+		if m.Amount.Sub(lower).Cmp(upper.Sub(m.Amount)) < 0 {
+			price = lower
+		}
+	case RoundDown:
+		if lower.Sign() > 0 {
+			price = lower
+		}
+	}
+
+	if !r.Min.IsZero() && price.Cmp(r.Min) < 0 {
+		price = r.Min
+	}
+	if !r.Max.IsZero() && price.Cmp(r.Max) > 0 {
+		price = r.Max
+	}
+
+	return currconv.Money{Amount: price.Round(places), Currency: m.Currency}
+}
+
+// Item is a catalog item.
+type Item struct {
+	ID    string
+	Price currconv.Money
+}
+
+// Price is a localized price of an Item.
+type Price struct {
+	ID       string
+	Original currconv.Money
+	// Rate is the rate used to convert Original, including the markup.
+	Rate currconv.Decimal
+	// Raw is the converted price, not rounded.
+	Raw currconv.Money
+	// Final is Raw after the rule of its currency is applied.
+	Final currconv.Money
+}
+
+// Pricer converts prices with rates from API and applies the rule of each currency.
+type Pricer struct {
+	// Rules are keyed by ISO 4217 code, a currency without a rule is rounded up to its minor units.
+	Rules map[string]Rule
+	// Markup is a percentage added to every rate, e.g. 2.5 for 2.5%.
+	Markup currconv.Decimal
+
+	api *currconv.API
+}
+
+// New create and return a Pricer with a copy of DefaultRules.
+func New(api *currconv.API) *Pricer {
+	rules := make(map[string]Rule, len(DefaultRules))
+	for currency, r := range DefaultRules {
+		rules[currency] = r
+	}
+
+	return &Pricer{
+		Rules: rules,
+		api:   api,
+	}
+}
+
+// Localize converts `price` into `to` and applies the rule of `to`.
+func (p *Pricer) Localize(price currconv.Money, to string) (Price, error) {
+	prices, err := p.LocalizeCatalog([]Item{{Price: price}}, to)
+	if err != nil {
+		return Price{}, err
+	}
+
+	return prices[0], nil
+}
+
+// LocalizeCatalog converts every item into each of `to` and applies the rule of each currency.
+// Rates are fetched in as few requests as possible with ConvertMany.
+// The result is sorted by the order of `items` and then by the order of `to`.
+func (p *Pricer) LocalizeCatalog(items []Item, to ...string) ([]Price, error) {
+	if len(to) == 0 {
+		return nil, errors.New("`to` require at least one currency")
+	}
+
+	var q []string
+	seen := map[string]bool{}
+	for _, item := range items {
+		for _, currency := range to {
+			if pair := item.Price.Currency + "_" + currency; item.Price.Currency != currency && !seen[pair] {
+				q = append(q, pair)
+				seen[pair] = true
+			}
+		}
+	}
+
+	rates := currconv.ConvertCompactExact{}
+	if len(q) > 0 {
+		var err error
+		rates, err = p.api.ConvertMany(currconv.ConvertRequest{Q: q})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	markup := currconv.NewDecimalFromInt(1).Add(p.Markup.Mul(currconv.MustParseDecimal("0.01")))
+
+	prices := make([]Price, 0, len(items)*len(to))
+	for _, item := range items {
+		for _, currency := range to {
+			rate := currconv.NewDecimalFromInt(1)
+			if item.Price.Currency != currency {
+				r, ok := rates[item.Price.Currency+"_"+currency]
+				if !ok {
+					return nil, fmt.Errorf("%s_%s: %w", item.Price.Currency, currency, currconv.ErrRateNotFound)
+				}
+				rate = r.Mul(markup)
+			}
+
+			raw := currconv.Money{Amount: item.Price.Amount.Mul(rate), Currency: currency}
+			prices = append(prices, Price{
+				ID:       item.ID,
+				Original: item.Price,
+				Rate:     rate,
+				Raw:      raw,
+				Final:    p.Rules[currency].Apply(raw),
+			})
+		}
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,152 @@
+package pricing
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	currconv "github.com/kitloong/go-currency-converter-api/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// ratesServer serves compact current rates from `rates`.
+func ratesServer(rates map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result := map[string]json.RawMessage{}
+		for _, pair := range strings.Split(r.URL.Query().Get("q"), ",") {
+			if rate, ok := rates[pair]; ok {
+				result[pair] = json.RawMessage(rate)
+			}
+		}
+
+		b, _ := json.Marshal(result)
+		_, _ = w.Write(b)
+	}))
+}
+
+func money(amount string, currency string) currconv.Money {
+	return currconv.NewMoney(currconv.MustParseDecimal(amount), currency)
+}
+
+func TestRule_Apply(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  Rule
+		price currconv.Money
+		final string
+	}{
+		{"USD charm", DefaultRules["USD"], money("12.34", "USD"), "12.99"},
+		{"USD charm exact", DefaultRules["USD"], money("12.99", "USD"), "12.99"},
+		{"USD charm next", DefaultRules["USD"], money("13.00", "USD"), "13.99"},
+		{"USD charm below one", DefaultRules["USD"], money("0.2", "USD"), "0.99"},
+		{"JPY hundreds", DefaultRules["JPY"], money("1234.5", "JPY"), "1300"},
+		{"JPY hundreds exact", DefaultRules["JPY"], money("1200", "JPY"), "1200"},
+		{"CHF .90", DefaultRules["CHF"], money("12.95", "CHF"), "13.90"},
+		{"CHF .90 same unit", DefaultRules["CHF"], money("12.34", "CHF"), "12.90"},
+		{"Minor units", Rule{}, money("12.341", "MYR"), "12.35"},
+		{"Minor units of KWD", Rule{}, money("1.2341", "KWD"), "1.235"},
+		{
+			"Nearest",
+			Rule{Step: currconv.NewDecimalFromInt(1), Ending: currconv.MustParseDecimal("0.99"), Rounding: RoundNearest},
+			money("13.40", "USD"),
+			"12.99",
+		},
+		{
+			"Nearest half",
+			Rule{Step: currconv.NewDecimalFromInt(10), Rounding: RoundNearest},
+			money("15", "USD"),
+			"20.00",
+		},
+		{
+			"Down",
+			Rule{Step: currconv.NewDecimalFromInt(1), Ending: currconv.MustParseDecimal("0.99"), Rounding: RoundDown},
+			money("13.98", "USD"),
+			"12.99",
+		},
+		{
+			"Down below the first price",
+			Rule{Step: currconv.NewDecimalFromInt(1), Ending: currconv.MustParseDecimal("0.99"), Rounding: RoundDown},
+			money("0.5", "USD"),
+			"0.99",
+		},
+		{
+			"Min",
+			Rule{Step: currconv.NewDecimalFromInt(1), Ending: currconv.MustParseDecimal("0.99"), Min: currconv.MustParseDecimal("4.99")},
+			money("1.2", "USD"),
+			"4.99",
+		},
+		{
+			"Max",
+			Rule{Step: currconv.NewDecimalFromInt(100), Max: currconv.NewDecimalFromInt(5000)},
+			money("6001", "JPY"),
+			"5000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			final := tt.rule.Apply(tt.price)
+			assert.Equal(t, tt.price.Currency, final.Currency)
+			assert.Equal(t, tt.final, final.Amount.String())
+		})
+	}
+}
+
+func TestPricer_LocalizeCatalog(t *testing.T) {
+	ts := ratesServer(map[string]string{
+		"USD_EUR": "0.9312",
+		"USD_JPY": "133.9",
+		"USD_CHF": "0.9231",
+		"MYR_EUR": "0.2151",
+		"MYR_JPY": "30.91",
+		"MYR_CHF": "0.2130",
+		"MYR_USD": "0.2250",
+	})
+	defer ts.Close()
+
+	api := currconv.NewAPI(currconv.Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	p := New(api)
+	p.Markup = currconv.MustParseDecimal("2.5")
+
+	prices, err := p.LocalizeCatalog([]Item{
+		{ID: "A", Price: money("19.99", "USD")},
+		{ID: "B", Price: money("45.00", "MYR")},
+	}, "EUR", "JPY", "CHF", "USD")
+	assert.NoError(t, err)
+	assert.Len(t, prices, 8)
+
+	type result struct{ id, rate, raw, final string }
+	var results []result
+	for _, p := range prices {
+		results = append(results, result{p.ID + ":" + p.Final.Currency, p.Rate.String(), p.Raw.Amount.String(), p.Final.Amount.String()})
+	}
+
+	assert.Equal(t, []result{
+		{"A:EUR", "0.9544800", "19.080055200", "19.99"},
+		{"A:JPY", "137.2475", "2743.577525", "2800"},
+		{"A:CHF", "0.9461775", "18.914088225", "19.90"},
+		{"A:USD", "1", "19.99", "19.99"},
+		{"B:EUR", "0.2204775", "9.921487500", "9.99"},
+		{"B:JPY", "31.68275", "1425.7237500", "1500"},
+		{"B:CHF", "0.2183250", "9.824625000", "9.90"},
+		{"B:USD", "0.2306250", "10.378125000", "10.99"},
+	}, results)
+
+	_, err = p.LocalizeCatalog([]Item{{ID: "A", Price: money("1", "USD")}})
+	assert.EqualError(t, err, "`to` require at least one currency")
+
+	_, err = p.Localize(money("1", "USD"), "GBP")
+	assert.EqualError(t, err, "USD_GBP: rate not found")
+
+	price, err := p.Localize(money("10", "USD"), "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, "9.99", price.Final.Amount.String())
+	assert.Equal(t, money("10", "USD"), price.Original)
+}
@@ -0,0 +1,239 @@
+// Package quote quotes customer rates with spreads and fees on top of the mid rates of CurrencyConverterAPI,
+// signed with an HMAC so quotes could be verified when they are executed.
+package quote
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	currconv "github.com/kitloong/go-currency-converter-api/v2"
+)
+
+var (
+	// ErrInvalidSignature is returned by Verify when a quote was not signed by the Quoter, or was modified.
+	ErrInvalidSignature = errors.New("invalid quote signature")
+	// ErrExpired is returned by Verify when a quote is past its ExpiresAt.
+	ErrExpired = errors.New("quote expired")
+)
+
+const (
+	// defaultTTL is the default time a quote is valid.
+	defaultTTL = time.Minute
+	// ratePlaces is the number of digits after the decimal point of customer rates, same as the rates of CurrencyConverterAPI.
+	// Customer rates are rounded down.
+	ratePlaces = 6
+)
+
+// Fee is charged in the currency converted from, before conversion.
+type Fee struct {
+	// Fixed is a fixed amount, e.g. 5 for 5.00 USD.
+	Fixed currconv.Decimal
+	// Percent is a percentage of the amount, e.g. 0.5 for 0.5%.
+	Percent currconv.Decimal
+}
+
+// Quote is a signed offer to convert Amount from From to To.
+type Quote struct {
+	ID   string `json:"id"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Amount is in From, including Fee.
+	Amount currconv.Decimal `json:"amount"`
+	// Fee is in From, and deducted from Amount before conversion.
+	Fee currconv.Decimal `json:"fee"`
+	// MidRate is the rate from CurrencyConverterAPI.
+	MidRate currconv.Decimal `json:"midRate"`
+	// Spread is the percentage deducted from MidRate.
+	Spread currconv.Decimal `json:"spread"`
+	// Rate is the customer rate, MidRate less Spread.
+	Rate currconv.Decimal `json:"rate"`
+	// Converted is Amount less Fee at Rate, in To, rounded down to its minor units.
+	Converted currconv.Decimal `json:"converted"`
+	CreatedAt time.Time        `json:"createdAt"`
+	ExpiresAt time.Time        `json:"expiresAt"`
+	// Signature is the hex encoded HMAC-SHA256 of every other field.
+	Signature string `json:"signature"`
+}
+
+// Quoter quotes conversions with rates from API.
+// The spread of a pair is looked up in Spreads, then in GroupSpreads, and then DefaultSpread.
+type Quoter struct {
+	// Spreads are percentages keyed by pair in "[FROM]_[TO]" format, e.g. "USD_MYR": 0.75.
+	Spreads map[string]currconv.Decimal
+	// Groups maps currencies to group names, e.g. "USD": "major" and "MYR": "asia".
+	Groups map[string]string
+	// GroupSpreads are percentages keyed by group pair in "[FROM GROUP]_[TO GROUP]" format, e.g. "major_asia": 1.5.
+	GroupSpreads map[string]currconv.Decimal
+	// DefaultSpread is the percentage of pairs without a spread.
+	DefaultSpread currconv.Decimal
+	// Fees are keyed by the currency converted from, currencies without a fee are not charged.
+	Fees map[string]Fee
+	// TTL is how long a quote is valid, default to 1 minute.
+	TTL time.Duration
+
+	api *currconv.API
+	key []byte
+	now func() time.Time
+}
+
+// New create and return a Quoter signing quotes with `key`.
+func New(api *currconv.API, key []byte) *Quoter {
+	return &Quoter{
+		Spreads:      map[string]currconv.Decimal{},
+		Groups:       map[string]string{},
+		GroupSpreads: map[string]currconv.Decimal{},
+		Fees:         map[string]Fee{},
+		api:          api,
+		key:          key,
+		now:          time.Now,
+	}
+}
+
+// Quote fetches the mid rate of `from` to `to` with ConvertCompactExact, and returns a signed quote to convert `amount`.
+func (q *Quoter) Quote(from string, to string, amount currconv.Decimal) (Quote, error) {
+	if from == to {
+		return Quote{}, fmt.Errorf("`from` and `to` are both %s", from)
+	}
+
+	if amount.Sign() <= 0 {
+		return Quote{}, errors.New("`amount` must be positive")
+	}
+
+	pair := from + "_" + to
+	rates, err := q.api.ConvertCompactExact(currconv.ConvertRequest{Q: []string{pair}})
+	if err != nil {
+		return Quote{}, err
+	}
+
+	mid, ok := rates[pair]
+	if !ok {
+		return Quote{}, fmt.Errorf("%s: %w", pair, currconv.ErrRateNotFound)
+	}
+
+	fee := q.fee(from, amount)
+	if fee.Cmp(amount) >= 0 {
+		return Quote{}, fmt.Errorf("fee %s %s exceeds the amount", fee, from)
+	}
+
+	spread := q.spread(from, to)
+	rate := roundDown(mid.Sub(percentOf(mid, spread)), ratePlaces)
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Quote{}, err
+	}
+
+	now := q.now().UTC()
+	ttl := q.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+
+	quote := Quote{
+		ID:        hex.EncodeToString(id),
+		From:      from,
+		To:        to,
+		Amount:    amount,
+		Fee:       fee,
+		MidRate:   mid,
+		Spread:    spread,
+		Rate:      rate,
+		Converted: roundDown(amount.Sub(fee).Mul(rate), currconv.MinorUnits(to)),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	quote.Signature = q.sign(quote)
+
+	return quote, nil
+}
+
+// Verify returns ErrInvalidSignature if `quote` was not signed by the Quoter or was modified,
+// or ErrExpired if it is past its ExpiresAt.
+func (q *Quoter) Verify(quote Quote) error {
+	signature, err := hex.DecodeString(quote.Signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected, _ := hex.DecodeString(q.sign(quote))
+	if !hmac.Equal(signature, expected) {
+		return ErrInvalidSignature
+	}
+
+	if !q.now().Before(quote.ExpiresAt) {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+// spread returns the spread percentage of `from` to `to`.
+func (q *Quoter) spread(from string, to string) currconv.Decimal {
+	if s, ok := q.Spreads[from+"_"+to]; ok {
+		return s
+	}
+
+	if s, ok := q.GroupSpreads[q.Groups[from]+"_"+q.Groups[to]]; ok {
+		return s
+	}
+
+	return q.DefaultSpread
+}
+
+// fee returns the fee of converting `amount` from `from`, rounded up to the minor units of `from`.
+func (q *Quoter) fee(from string, amount currconv.Decimal) currconv.Decimal {
+	f := q.Fees[from]
+	return roundUp(f.Fixed.Add(percentOf(amount, f.Percent)), currconv.MinorUnits(from))
+}
+
+// sign returns the hex encoded HMAC-SHA256 of every field of `quote` except Signature.
+func (q *Quoter) sign(quote Quote) string {
+	fields := []string{
+		quote.ID,
+		quote.From,
+		quote.To,
+		quote.Amount.String(),
+		quote.Fee.String(),
+		quote.MidRate.String(),
+		quote.Spread.String(),
+		quote.Rate.String(),
+		quote.Converted.String(),
+		quote.CreatedAt.UTC().Format(time.RFC3339Nano),
+		quote.ExpiresAt.UTC().Format(time.RFC3339Nano),
+	}
+
+	mac := hmac.New(sha256.New, q.key)
+	mac.Write([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// percentOf returns `percent`% of `d`, exactly.
+func percentOf(d currconv.Decimal, percent currconv.Decimal) currconv.Decimal {
+	return d.Mul(percent).Mul(currconv.MustParseDecimal("0.01"))
+}
+
+// roundDown rounds positive `d` down to `places`, so the customer is never paid more than quoted.
+func roundDown(d currconv.Decimal, places int32) currconv.Decimal {
+	r := d.Round(places)
+	if r.Cmp(d) > 0 {
+		r = r.Sub(currconv.MustParseDecimal(fmt.Sprintf("1e-%d", places)))
+	}
+
+	return r
+}
+
+// roundUp rounds positive `d` up to `places`, so fees are never less than configured.
+func roundUp(d currconv.Decimal, places int32) currconv.Decimal {
+	r := d.Round(places)
+	if r.Cmp(d) < 0 {
+		r = r.Add(currconv.MustParseDecimal(fmt.Sprintf("1e-%d", places)))
+	}
+
+	return r
+}
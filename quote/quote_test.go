@@ -0,0 +1,125 @@
+package quote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	currconv "github.com/kitloong/go-currency-converter-api/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"USD_MYR": 4.348493, "USD_JPY": 133.901, "EUR_JPY": 143.77, "MYR_USD": 0.229964}`))
+	}))
+	defer ts.Close()
+
+	api := currconv.NewAPI(currconv.Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	now := time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)
+	q := New(api, []byte("secret"))
+	q.now = func() time.Time { return now }
+	q.Spreads["USD_MYR"] = currconv.MustParseDecimal("0.75")
+	q.Groups["USD"], q.Groups["EUR"], q.Groups["JPY"] = "major", "major", "major"
+	q.GroupSpreads["major_major"] = currconv.MustParseDecimal("0.25")
+	q.DefaultSpread = currconv.MustParseDecimal("2")
+	q.Fees["USD"] = Fee{Fixed: currconv.MustParseDecimal("1.50"), Percent: currconv.MustParseDecimal("0.333")}
+
+	tests := []struct {
+		name      string
+		from      string
+		to        string
+		amount    string
+		fee       string
+		spread    string
+		rate      string
+		converted string
+	}{
+		{"Pair spread", "USD", "MYR", "1000", "4.83", "0.75", "4.315879", "4295.03"},
+		{"Group spread", "USD", "JPY", "100.00", "1.84", "0.25", "133.566247", "13110"},
+		{"Group spread without fee", "EUR", "JPY", "10", "0.00", "0.25", "143.410575", "1434"},
+		{"Default spread", "MYR", "USD", "100", "0.00", "2", "0.225364", "22.53"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := q.Quote(tt.from, tt.to, currconv.MustParseDecimal(tt.amount))
+			assert.NoError(t, err)
+			assert.Len(t, quote.ID, 32)
+			assert.Equal(t, tt.fee, quote.Fee.String())
+			assert.Equal(t, tt.spread, quote.Spread.String())
+			assert.Equal(t, tt.rate, quote.Rate.String())
+			assert.Equal(t, tt.converted, quote.Converted.String())
+			assert.Equal(t, now, quote.CreatedAt)
+			assert.Equal(t, now.Add(time.Minute), quote.ExpiresAt)
+			assert.NoError(t, q.Verify(quote))
+		})
+	}
+
+	_, err := q.Quote("USD", "USD", currconv.MustParseDecimal("1"))
+	assert.EqualError(t, err, "`from` and `to` are both USD")
+
+	_, err = q.Quote("USD", "MYR", currconv.MustParseDecimal("0"))
+	assert.EqualError(t, err, "`amount` must be positive")
+
+	_, err = q.Quote("USD", "MYR", currconv.MustParseDecimal("1.5"))
+	assert.EqualError(t, err, "fee 1.51 USD exceeds the amount")
+
+	_, err = q.Quote("USD", "GBP", currconv.MustParseDecimal("100"))
+	assert.EqualError(t, err, "USD_GBP: rate not found")
+}
+
+func TestQuoter_Verify(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"USD_MYR": 4.348493}`))
+	}))
+	defer ts.Close()
+
+	api := currconv.NewAPI(currconv.Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	})
+
+	now := time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)
+	q := New(api, []byte("secret"))
+	q.now = func() time.Time { return now }
+	q.TTL = 30 * time.Second
+	q.DefaultSpread = currconv.MustParseDecimal("1")
+
+	quote, err := q.Quote("USD", "MYR", currconv.MustParseDecimal("100.00"))
+	assert.NoError(t, err)
+
+	b, err := json.Marshal(quote)
+	assert.NoError(t, err)
+
+	var decoded Quote
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.NoError(t, q.Verify(decoded), "a quote must survive a round trip to the client")
+
+	tampered := decoded
+	tampered.Rate = currconv.MustParseDecimal("4.5")
+	assert.ErrorIs(t, q.Verify(tampered), ErrInvalidSignature)
+
+	tampered = decoded
+	tampered.ExpiresAt = tampered.ExpiresAt.Add(time.Hour)
+	assert.ErrorIs(t, q.Verify(tampered), ErrInvalidSignature)
+
+	tampered = decoded
+	tampered.Signature = "not hex"
+	assert.ErrorIs(t, q.Verify(tampered), ErrInvalidSignature)
+
+	other := New(api, []byte("other secret"))
+	other.now = q.now
+	assert.ErrorIs(t, other.Verify(decoded), ErrInvalidSignature)
+
+	now = now.Add(30 * time.Second)
+	assert.ErrorIs(t, q.Verify(decoded), ErrExpired)
+}
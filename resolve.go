@@ -0,0 +1,229 @@
+package currconv
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Reason is the reason a display currency was chosen by Resolver.
+type Reason string
+
+const (
+	// ReasonOverride is an explicit currency, e.g. the `?currency=` query parameter.
+	ReasonOverride Reason = "override"
+	// ReasonCountry is the currency of the country of the visitor, e.g. from geo-IP.
+	ReasonCountry Reason = "country"
+	// ReasonLanguage is the currency of the region of a language in the Accept-Language header.
+	ReasonLanguage Reason = "language"
+	// ReasonDefault is the default currency, when nothing else is allowed.
+	ReasonDefault Reason = "default"
+)
+
+// defaultQueryParameter is the default override query parameter of Resolver.Middleware.
+const defaultQueryParameter = "currency"
+
+// DisplayCurrency is the result of Resolver.Resolve.
+type DisplayCurrency struct {
+	Currency string
+	Reason   Reason
+	// Source is the value the currency was chosen from, e.g. "EUR" for ReasonOverride, "MY" for ReasonCountry
+	// or "de-CH" for ReasonLanguage. It is empty for ReasonDefault.
+	Source string
+}
+
+// ResolveRequest contains request fields of Resolver.Resolve, all fields are optional.
+type ResolveRequest struct {
+	// Override is an explicit ISO 4217 code, e.g. from a query parameter or a user setting.
+	Override string
+	// Country is an ISO 3166-1 alpha-2 or alpha-3 code, e.g. from geo-IP.
+	Country string
+	// AcceptLanguage is the value of the Accept-Language header, e.g. "de-CH,de;q=0.9,en;q=0.8".
+	AcceptLanguage string
+}
+
+// Resolver picks the currency to display prices in, by the override, the country and then the Accept-Language
+// of a visitor. Candidates not in Allowed are skipped.
+// The zero value uses the countries of the embedded dataset, same as NewResolver(DatasetCountries()).
+type Resolver struct {
+	// Allowed are the currencies which could be displayed, every currency of the countries if empty.
+	Allowed []string
+	// Default is the currency when no candidate is allowed, default to the first of Allowed, or "USD".
+	Default string
+	// QueryParameter is the override query parameter read by Middleware, default to "currency".
+	QueryParameter string
+	// Country returns the country of a request for Middleware, e.g. from a header set by the geo-IP layer.
+	Country func(r *http.Request) string
+
+	index     *Index
+	indexOnce sync.Once
+}
+
+// NewResolver create and return a Resolver with the currency of each country from the result of Countries,
+// e.g. NewResolver(DatasetCountries()).
+func NewResolver(countries *Country) *Resolver {
+	return &Resolver{
+		index: NewIndex(nil, countries),
+	}
+}
+
+// Resolve returns the best currency of `req`, with the reason it was chosen.
+// Languages of AcceptLanguage are tried by their quality, a language without region uses its main locale, e.g. "ja" is "ja-JP".
+func (r *Resolver) Resolve(req ResolveRequest) DisplayCurrency {
+	if code := strings.ToUpper(strings.TrimSpace(req.Override)); code != "" {
+		if _, ok := r.countries().Currency(code); ok && r.allowed(code) {
+			return DisplayCurrency{Currency: code, Reason: ReasonOverride, Source: code}
+		}
+	}
+
+	if req.Country != "" {
+		if c, ok := r.countries().Country(req.Country); ok && r.allowed(c.CurrencyID) {
+			return DisplayCurrency{Currency: c.CurrencyID, Reason: ReasonCountry, Source: c.ID}
+		}
+	}
+
+	for _, tag := range parseAcceptLanguage(req.AcceptLanguage) {
+		if c, ok := r.countries().Country(languageRegion(tag)); ok && r.allowed(c.CurrencyID) {
+			return DisplayCurrency{Currency: c.CurrencyID, Reason: ReasonLanguage, Source: tag}
+		}
+	}
+
+	return DisplayCurrency{Currency: r.defaultCurrency(), Reason: ReasonDefault}
+}
+
+// countries returns the Index of the countries, built from the embedded dataset if the Resolver was not created by NewResolver.
+func (r *Resolver) countries() *Index {
+	r.indexOnce.Do(func() {
+		if r.index == nil {
+			r.index = NewIndex(nil, DatasetCountries())
+		}
+	})
+
+	return r.index
+}
+
+// Middleware resolves the currency of each request and puts it into the request context,
+// read it with DisplayCurrencyFromContext.
+func (r *Resolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		param := r.QueryParameter
+		if param == "" {
+			param = defaultQueryParameter
+		}
+
+		rr := ResolveRequest{
+			Override:       req.URL.Query().Get(param),
+			AcceptLanguage: req.Header.Get("Accept-Language"),
+		}
+		if r.Country != nil {
+			rr.Country = r.Country(req)
+		}
+
+		ctx := context.WithValue(req.Context(), displayCurrencyKey{}, r.Resolve(rr))
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// displayCurrencyKey is the context key of DisplayCurrency.
+type displayCurrencyKey struct{}
+
+// DisplayCurrencyFromContext returns the DisplayCurrency put by Resolver.Middleware.
+// The second return value reports whether there is one.
+func DisplayCurrencyFromContext(ctx context.Context) (DisplayCurrency, bool) {
+	d, ok := ctx.Value(displayCurrencyKey{}).(DisplayCurrency)
+	return d, ok
+}
+
+// allowed reports whether `currency` is in Allowed.
+func (r *Resolver) allowed(currency string) bool {
+	if currency == "" {
+		return false
+	}
+
+	if len(r.Allowed) == 0 {
+		return true
+	}
+
+	for _, c := range r.Allowed {
+		if strings.EqualFold(c, currency) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// defaultCurrency returns the configured Default or its default.
+func (r *Resolver) defaultCurrency() string {
+	if r.Default != "" {
+		return r.Default
+	}
+
+	if len(r.Allowed) > 0 {
+		return r.Allowed[0]
+	}
+
+	return "USD"
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language header, sorted by quality.
+// Tags with quality 0 and the wildcard are omitted.
+func parseAcceptLanguage(header string) []string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	var languages []language
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			q, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+
+		if quality > 0 {
+			languages = append(languages, language{tag: tag, quality: quality})
+		}
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	tags := make([]string, len(languages))
+	for i, l := range languages {
+		tags[i] = l.tag
+	}
+
+	return tags
+}
+
+// languageRegion returns the region of language `tag`, e.g. "CH" of "de-CH", or "JP" of "ja" by its main locale.
+func languageRegion(tag string) string {
+	subtags := strings.Split(strings.ReplaceAll(tag, "_", "-"), "-")
+	for _, s := range subtags[1:] {
+		if len(s) == 2 && isLetters(s) {
+			return strings.ToUpper(s)
+		}
+	}
+
+	if locale, ok := languageLocales[strings.ToLower(subtags[0])]; ok {
+		_, region, _ := strings.Cut(locale, "-")
+		return region
+	}
+
+	return ""
+}
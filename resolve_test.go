@@ -0,0 +1,132 @@
+package currconv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		req     ResolveRequest
+		result  DisplayCurrency
+	}{
+		{
+			"Override",
+			nil,
+			ResolveRequest{Override: "jpy", Country: "MY", AcceptLanguage: "de-DE"},
+			DisplayCurrency{Currency: "JPY", Reason: ReasonOverride, Source: "JPY"},
+		},
+		{
+			"Unknown override falls back to country",
+			nil,
+			ResolveRequest{Override: "XYZ", Country: "MY"},
+			DisplayCurrency{Currency: "MYR", Reason: ReasonCountry, Source: "MY"},
+		},
+		{
+			"Override not allowed",
+			[]string{"USD", "EUR"},
+			ResolveRequest{Override: "JPY"},
+			DisplayCurrency{Currency: "USD", Reason: ReasonDefault},
+		},
+		{
+			"Country by alpha-3",
+			nil,
+			ResolveRequest{Country: "deu"},
+			DisplayCurrency{Currency: "EUR", Reason: ReasonCountry, Source: "DE"},
+		},
+		{
+			"Country not allowed falls back to language",
+			[]string{"USD", "EUR", "CHF"},
+			ResolveRequest{Country: "MY", AcceptLanguage: "de-CH,de;q=0.9,en;q=0.8"},
+			DisplayCurrency{Currency: "CHF", Reason: ReasonLanguage, Source: "de-CH"},
+		},
+		{
+			"Language by quality",
+			nil,
+			ResolveRequest{AcceptLanguage: "en;q=0.5, fr-CA;q=0.9, *;q=1"},
+			DisplayCurrency{Currency: "CAD", Reason: ReasonLanguage, Source: "fr-CA"},
+		},
+		{
+			"Language without region",
+			nil,
+			ResolveRequest{AcceptLanguage: "ja"},
+			DisplayCurrency{Currency: "JPY", Reason: ReasonLanguage, Source: "ja"},
+		},
+		{
+			"Language with script",
+			nil,
+			ResolveRequest{AcceptLanguage: "zh-Hant-TW"},
+			DisplayCurrency{Currency: "TWD", Reason: ReasonLanguage, Source: "zh-Hant-TW"},
+		},
+		{
+			"Language with quality 0 is skipped",
+			[]string{"EUR", "GBP"},
+			ResolveRequest{AcceptLanguage: "en-GB;q=0, de-DE;q=0.1"},
+			DisplayCurrency{Currency: "EUR", Reason: ReasonLanguage, Source: "de-DE"},
+		},
+		{
+			"Default",
+			nil,
+			ResolveRequest{},
+			DisplayCurrency{Currency: "USD", Reason: ReasonDefault},
+		},
+		{
+			"Default to the first allowed",
+			[]string{"EUR", "USD"},
+			ResolveRequest{AcceptLanguage: "ja-JP"},
+			DisplayCurrency{Currency: "EUR", Reason: ReasonDefault},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewResolver(DatasetCountries())
+			r.Allowed = tt.allowed
+			assert.Equal(t, tt.result, r.Resolve(tt.req))
+		})
+	}
+}
+
+func TestResolver_Literal(t *testing.T) {
+	r := &Resolver{Allowed: []string{"EUR"}}
+	assert.Equal(t, DisplayCurrency{Currency: "EUR", Reason: ReasonCountry, Source: "DE"}, r.Resolve(ResolveRequest{Country: "DE"}))
+	assert.Equal(t, DisplayCurrency{Currency: "EUR", Reason: ReasonDefault}, r.Resolve(ResolveRequest{Country: "MY"}))
+}
+
+func TestResolver_Middleware(t *testing.T) {
+	r := NewResolver(DatasetCountries())
+	r.Default = "EUR"
+	r.QueryParameter = "cur"
+	r.Country = func(req *http.Request) string {
+		return req.Header.Get("CF-IPCountry")
+	}
+
+	var got DisplayCurrency
+	var ok bool
+	handler := r.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got, ok = DisplayCurrencyFromContext(req.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/products?cur=gbp", nil)
+	req.Header.Set("CF-IPCountry", "MY")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, ok)
+	assert.Equal(t, DisplayCurrency{Currency: "GBP", Reason: ReasonOverride, Source: "GBP"}, got)
+
+	req = httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("CF-IPCountry", "MY")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, DisplayCurrency{Currency: "MYR", Reason: ReasonCountry, Source: "MY"}, got)
+
+	req = httptest.NewRequest(http.MethodGet, "/products", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, DisplayCurrency{Currency: "EUR", Reason: ReasonDefault}, got)
+
+	_, ok = DisplayCurrencyFromContext(req.Context())
+	assert.False(t, ok)
+}
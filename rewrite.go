@@ -0,0 +1,313 @@
+package currconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// HeaderCurrency is the request header of the target currency of PriceRewriter,
+	// and the response header of the currency prices are in.
+	HeaderCurrency = "X-Currency"
+	// HeaderRate is the response header of the rate prices are converted at.
+	HeaderRate = "X-Currency-Rate"
+	// HeaderRateTime is the response header of the time the rate was fetched, in RFC 3339 format.
+	HeaderRateTime = "X-Currency-Rate-Time"
+
+	// defaultRateTTL is the default time a rate is reused by PriceRewriter.
+	defaultRateTTL = time.Hour
+)
+
+// PriceRewriter converts price fields of JSON responses into the currency requested by the client.
+// The target currency is read from the HeaderCurrency header, then the `?currency=` query parameter,
+// and then the DisplayCurrency put by Resolver.Middleware.
+// Create it with NewPriceRewriter, a PriceRewriter without API passes responses through unchanged.
+type PriceRewriter struct {
+	// From is the currency of prices in responses, e.g. "USD".
+	From string
+	// Paths are the JSON paths of price fields, e.g. "total", "items[*].price" or "data.items[0].price".
+	// Price fields could be JSON numbers or strings of numbers, other values are left as is.
+	Paths []string
+	// QueryParameter is the query parameter of the target currency, default to "currency".
+	QueryParameter string
+	// TTL is how long a fetched rate is reused, default to 1 hour.
+	TTL time.Duration
+
+	api   *API
+	mu    sync.Mutex
+	rates map[string]cachedRate
+	now   func() time.Time
+}
+
+// cachedRate is a rate fetched by PriceRewriter.
+type cachedRate struct {
+	rate      Decimal
+	fetchedAt time.Time
+}
+
+// NewPriceRewriter create and return a PriceRewriter converting fields at `paths` from `from`.
+func NewPriceRewriter(api *API, from string, paths ...string) *PriceRewriter {
+	return &PriceRewriter{
+		From:  from,
+		Paths: paths,
+		api:   api,
+		rates: map[string]cachedRate{},
+		now:   time.Now,
+	}
+}
+
+// Middleware rewrites price fields of successful JSON responses of `next`, rounded to the minor units of the target currency,
+// and sets the HeaderCurrency, HeaderRate and HeaderRateTime response headers.
+// Responses are passed through unchanged, with HeaderCurrency of From, if the target currency is unknown
+// or its rate could not be fetched.
+func (p *PriceRewriter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", HeaderCurrency)
+
+		to := p.target(r)
+		if to == "" || to == p.From {
+			w.Header().Set(HeaderCurrency, p.From)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buf := &bufferedResponse{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+
+		body := buf.body.Bytes()
+		w.Header().Set(HeaderCurrency, p.From)
+		if buf.status == http.StatusOK && strings.Contains(w.Header().Get("Content-Type"), "json") {
+			if rate, err := p.rate(to); err == nil {
+				if b, err := p.rewrite(body, to, rate.rate); err == nil {
+					body = b
+					w.Header().Set(HeaderCurrency, to)
+					w.Header().Set(HeaderRate, rate.rate.String())
+					w.Header().Set(HeaderRateTime, rate.fetchedAt.UTC().Format(time.RFC3339))
+				}
+			}
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(buf.status)
+		_, _ = w.Write(body)
+	})
+}
+
+// target returns the target currency of `r`, or an empty string if there is none or it is unknown.
+func (p *PriceRewriter) target(r *http.Request) string {
+	param := p.QueryParameter
+	if param == "" {
+		param = defaultQueryParameter
+	}
+
+	to := r.Header.Get(HeaderCurrency)
+	if to == "" {
+		to = r.URL.Query().Get(param)
+	}
+	if to == "" {
+		if d, ok := DisplayCurrencyFromContext(r.Context()); ok {
+			to = d.Currency
+		}
+	}
+
+	to = strings.ToUpper(strings.TrimSpace(to))
+	if _, ok := FindISOCurrency(to); !ok {
+		return ""
+	}
+
+	return to
+}
+
+// rate returns the cached rate from From to `to`, or fetches it with ConvertCompactExact if it is older than TTL.
+func (p *PriceRewriter) rate(to string) (cachedRate, error) {
+	pair := p.From + "_" + to
+
+	ttl := p.TTL
+	if ttl <= 0 {
+		ttl = defaultRateTTL
+	}
+
+	p.mu.Lock()
+	cached, ok := p.rates[pair]
+	p.mu.Unlock()
+	if ok && p.clock().Sub(cached.fetchedAt) < ttl {
+		return cached, nil
+	}
+
+	if p.api == nil {
+		return cachedRate{}, errors.New("PriceRewriter requires an API, create it with NewPriceRewriter")
+	}
+
+	r, err := p.api.ConvertCompactExact(ConvertRequest{Q: []string{pair}})
+	if err != nil {
+		return cachedRate{}, err
+	}
+
+	rate, ok := r[pair]
+	if !ok {
+		return cachedRate{}, fmt.Errorf("%s: %w", pair, ErrRateNotFound)
+	}
+
+	cached = cachedRate{rate: rate, fetchedAt: p.clock()}
+	p.mu.Lock()
+	if p.rates == nil {
+		p.rates = map[string]cachedRate{}
+	}
+	p.rates[pair] = cached
+	p.mu.Unlock()
+
+	return cached, nil
+}
+
+// clock returns the current time, from `now` if it is set.
+func (p *PriceRewriter) clock() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+
+	return time.Now()
+}
+
+// rewrite converts the price fields of JSON `body` into `to` at `rate`.
+func (p *PriceRewriter) rewrite(body []byte, to string, rate Decimal) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+
+	var doc interface{}
+	if err := d.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	convert := func(v interface{}) interface{} {
+		switch price := v.(type) {
+		case json.Number:
+			amount, err := ParseDecimal(price.String())
+			if err != nil {
+				return v
+			}
+			return json.Number(Money{Amount: amount.Mul(rate), Currency: to}.Round().Amount.String())
+		case string:
+			amount, err := ParseDecimal(price)
+			if err != nil {
+				return v
+			}
+			return Money{Amount: amount.Mul(rate), Currency: to}.Round().Amount.String()
+		}
+		return v
+	}
+
+	for _, path := range p.Paths {
+		segments, err := parseJSONPath(path)
+		if err != nil {
+			return nil, err
+		}
+		doc = rewritePath(doc, segments, convert)
+	}
+
+	var out bytes.Buffer
+	e := json.NewEncoder(&out)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(doc); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// jsonPathSegment is an object key, an array index, or the array wildcard "[*]".
+type jsonPathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses `path` like "$.items[*].price" into segments, the leading "$." is optional.
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+
+	var segments []jsonPathSegment
+	for _, part := range strings.Split(path, ".") {
+		key, rest, _ := strings.Cut(part, "[")
+		if key != "" {
+			segments = append(segments, jsonPathSegment{key: key})
+		}
+
+		for rest != "" {
+			i := strings.Index(rest, "]")
+			if i < 0 {
+				return nil, fmt.Errorf("invalid JSON path %q", path)
+			}
+
+			if rest[:i] == "*" {
+				segments = append(segments, jsonPathSegment{wildcard: true})
+			} else {
+				n, err := strconv.Atoi(rest[:i])
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid JSON path %q", path)
+				}
+				segments = append(segments, jsonPathSegment{index: n, isIndex: true})
+			}
+
+			rest = strings.TrimPrefix(rest[i+1:], "[")
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid JSON path %q", path)
+	}
+
+	return segments, nil
+}
+
+// rewritePath replaces values of `v` at `segments` with `fn`. Missing keys and indexes are skipped.
+func rewritePath(v interface{}, segments []jsonPathSegment, fn func(interface{}) interface{}) interface{} {
+	if len(segments) == 0 {
+		return fn(v)
+	}
+
+	s := segments[0]
+	switch node := v.(type) {
+	case map[string]interface{}:
+		if child, ok := node[s.key]; ok && s.key != "" {
+			node[s.key] = rewritePath(child, segments[1:], fn)
+		}
+	case []interface{}:
+		for i := range node {
+			if s.wildcard || (s.isIndex && s.index == i) {
+				node[i] = rewritePath(node[i], segments[1:], fn)
+			}
+		}
+	}
+
+	return v
+}
+
+// bufferedResponse is an http.ResponseWriter keeping the body in memory, so it could be rewritten.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header implements http.ResponseWriter.
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+// Write implements http.ResponseWriter.
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
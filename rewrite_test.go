@@ -0,0 +1,244 @@
+package currconv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceRewriter_Middleware(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, "USD_MYR", r.URL.Query().Get("q"))
+		_, _ = w.Write([]byte(`{"USD_MYR": 4.4321}`))
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{BaseURL: ts.URL, APIKey: "key", Version: "v1"})
+
+	now := time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)
+	p := NewPriceRewriter(api, "USD", "total", "items[*].price", "$.meta.shipping[0]")
+	p.now = func() time.Time { return now }
+
+	handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total": 30.5,
+			"items": [{"name": "A & B", "price": 10}, {"name": "C", "price": "20.50"}, {"name": "D"}],
+			"meta": {"shipping": [5, 6], "count": 3}
+		}`))
+	}))
+
+	tests := []struct {
+		name     string
+		request  func() *http.Request
+		body     string
+		currency string
+		rate     string
+	}{
+		{
+			"Header",
+			func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.Header.Set(HeaderCurrency, "MYR")
+				return r
+			},
+			`{"items":[{"name":"A & B","price":44.32},{"name":"C","price":"90.86"},{"name":"D"}],"meta":{"count":3,"shipping":[22.16,6]},"total":135.18}` + "\n",
+			"MYR",
+			"4.4321",
+		},
+		{
+			"Query parameter",
+			func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/?currency=myr", nil)
+			},
+			`{"items":[{"name":"A & B","price":44.32},{"name":"C","price":"90.86"},{"name":"D"}],"meta":{"count":3,"shipping":[22.16,6]},"total":135.18}` + "\n",
+			"MYR",
+			"4.4321",
+		},
+		{
+			"Display currency",
+			func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				d := DisplayCurrency{Currency: "MYR", Reason: ReasonCountry, Source: "MY"}
+				return r.WithContext(context.WithValue(r.Context(), displayCurrencyKey{}, d))
+			},
+			`{"items":[{"name":"A & B","price":44.32},{"name":"C","price":"90.86"},{"name":"D"}],"meta":{"count":3,"shipping":[22.16,6]},"total":135.18}` + "\n",
+			"MYR",
+			"4.4321",
+		},
+		{
+			"Unknown currency",
+			func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/?currency=XYZ", nil)
+			},
+			"",
+			"USD",
+			"",
+		},
+		{
+			"Same currency",
+			func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/?currency=USD", nil)
+			},
+			"",
+			"USD",
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tt.request())
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.currency, w.Header().Get(HeaderCurrency))
+			assert.Equal(t, tt.rate, w.Header().Get(HeaderRate))
+			assert.Equal(t, HeaderCurrency, w.Header().Get("Vary"))
+			if tt.body != "" {
+				assert.Equal(t, tt.body, w.Body.String())
+				assert.Equal(t, "2023-02-15T02:00:00Z", w.Header().Get(HeaderRateTime))
+			} else {
+				assert.Contains(t, w.Body.String(), `"total": 30.5`)
+			}
+		})
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	now = now.Add(2 * time.Hour)
+	r := httptest.NewRequest(http.MethodGet, "/?currency=MYR", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestPriceRewriter_Passthrough(t *testing.T) {
+	tests := []struct {
+		name        string
+		upstream    http.HandlerFunc
+		contentType string
+		status      int
+		body        string
+	}{
+		{
+			"Upstream error",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"status": 400, "error": "Invalid API key"}`))
+			},
+			"application/json",
+			http.StatusOK,
+			`{"total": 10}`,
+		},
+		{
+			"Not JSON",
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"USD_MYR": 4.4321}`))
+			},
+			"text/plain",
+			http.StatusOK,
+			`{"total": 10}`,
+		},
+		{
+			"Handler error",
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"USD_MYR": 4.4321}`))
+			},
+			"application/json",
+			http.StatusNotFound,
+			`{"total": 10}`,
+		},
+		{
+			"Invalid JSON",
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"USD_MYR": 4.4321}`))
+			},
+			"application/json",
+			http.StatusOK,
+			`{"total": `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(tt.upstream)
+			defer ts.Close()
+
+			p := NewPriceRewriter(NewAPI(Config{BaseURL: ts.URL, APIKey: "key", Version: "v1"}), "USD", "total")
+			handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?currency=MYR", nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+			assert.Equal(t, "USD", w.Header().Get(HeaderCurrency))
+			assert.Empty(t, w.Header().Get(HeaderRate))
+		})
+	}
+}
+
+func TestPriceRewriter_Literal(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"USD_MYR": 4.4321}`))
+	}))
+	defer ts.Close()
+
+	serve := func(p *PriceRewriter) *httptest.ResponseRecorder {
+		handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"total": 10}`))
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?currency=MYR", nil))
+		return w
+	}
+
+	w := serve(&PriceRewriter{From: "USD", Paths: []string{"total"}})
+	assert.Equal(t, `{"total": 10}`, w.Body.String(), "a PriceRewriter without API passes through")
+	assert.Equal(t, "USD", w.Header().Get(HeaderCurrency))
+
+	api := NewAPI(Config{BaseURL: ts.URL, APIKey: "key", Version: "v1"})
+	w = serve(&PriceRewriter{From: "USD", Paths: []string{"total"}, api: api})
+	assert.Equal(t, `{"total":44.32}`+"\n", w.Body.String())
+	assert.Equal(t, "MYR", w.Header().Get(HeaderCurrency))
+}
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		segments []jsonPathSegment
+		error    string
+	}{
+		{"price", []jsonPathSegment{{key: "price"}}, ""},
+		{"$.items[*].price", []jsonPathSegment{{key: "items"}, {wildcard: true}, {key: "price"}}, ""},
+		{"rows[1][0]", []jsonPathSegment{{key: "rows"}, {index: 1, isIndex: true}, {index: 0, isIndex: true}}, ""},
+		{"items[x]", nil, `invalid JSON path "items[x]"`},
+		{"items[0", nil, `invalid JSON path "items[0"`},
+		{"$", nil, `invalid JSON path ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			segments, err := parseJSONPath(tt.path)
+			if tt.error != "" {
+				assert.EqualError(t, err, tt.error)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.segments, segments)
+		})
+	}
+}
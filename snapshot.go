@@ -0,0 +1,229 @@
+package currconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// snapshotVersion is the version of the RateCache snapshot format, increased on incompatible changes.
+const snapshotVersion = 1
+
+const defaultSnapshotInterval = 5 * time.Minute
+
+// snapshotClockSkew is how far in the future a rate of a snapshot may be fetched, e.g. saved by a host whose clock is ahead.
+const snapshotClockSkew = 5 * time.Minute
+
+// cacheSnapshot is the file format of a RateCache snapshot.
+type cacheSnapshot struct {
+	Version int                     `json:"version"`
+	SavedAt time.Time               `json:"savedAt"`
+	Rates   map[string]snapshotRate `json:"rates"`
+}
+
+// snapshotRate is a rate of cacheSnapshot with the time it was fetched from CurrencyConverterAPI.
+type snapshotRate struct {
+	Rate      json.RawMessage `json:"rate"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+}
+
+// WriteTo writes a snapshot of the cached rates and their fetch times to `w`, in a versioned JSON format.
+// Only rates are snapshotted, the Currencies and Countries metadata is not cached by RateCache and is available offline
+// from DatasetCurrencies and DatasetCountries.
+func (c *RateCache) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	s := cacheSnapshot{
+		Version: snapshotVersion,
+		SavedAt: c.clock(),
+		Rates:   make(map[string]snapshotRate, len(c.rates)),
+	}
+	for pair, r := range c.rates {
+		s.Rates[pair] = snapshotRate{Rate: r.rate, FetchedAt: r.fetchedAt}
+	}
+	c.mu.Unlock()
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(append(b, '\n'))
+	return int64(n), err
+}
+
+// ReadFrom loads a snapshot written by WriteTo from `r`, e.g. a seed file embedded in the binary.
+// Rates keep their original fetch times, so that SoftTTL and HardTTL apply as if they had never left the cache.
+// A cached rate fetched after the one in the snapshot is kept.
+// A snapshot with a rate which is not a number is rejected without loading any rate.
+// A rate fetched in the future by up to 5 minutes of clock skew is loaded as fetched now, a rate further in the future is skipped.
+func (c *RateCache) ReadFrom(r io.Reader) (int64, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(b)), err
+	}
+
+	var s cacheSnapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return int64(len(b)), err
+	}
+
+	if s.Version != snapshotVersion {
+		return int64(len(b)), fmt.Errorf("unsupported cache snapshot version %d", s.Version)
+	}
+
+	now := c.clock()
+	for _, pair := range sortedKeys(s.Rates) {
+		r := s.Rates[pair]
+		if _, err := ParseDecimal(string(r.Rate)); err != nil {
+			return int64(len(b)), fmt.Errorf("%s: invalid rate %s", pair, r.Rate)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.initMaps()
+	for pair, r := range s.Rates {
+		if r.FetchedAt.After(now.Add(snapshotClockSkew)) {
+			continue
+		}
+		if r.FetchedAt.After(now) {
+			r.FetchedAt = now
+		}
+
+		if cached, ok := c.rates[pair]; ok && !cached.fetchedAt.Before(r.FetchedAt) {
+			continue
+		}
+		c.rates[pair] = cacheEntry{rate: r.Rate, fetchedAt: r.FetchedAt}
+	}
+
+	return int64(len(b)), nil
+}
+
+// Save writes a snapshot to the file at `path` atomically, so that a crash never leaves a partial snapshot.
+func (c *RateCache) Save(path string) error {
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Load loads the snapshot at `path` written by Save, without writing to it.
+// It returns an error matching fs.ErrNotExist if there is no snapshot yet.
+func (c *RateCache) Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = c.ReadFrom(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
+
+// CacheSnapshotter saves snapshots of a RateCache to a file on a schedule, and once more when it stops.
+type CacheSnapshotter struct {
+	// Path is the file snapshots are saved to.
+	Path string
+	// Interval is the duration between two scheduled snapshots, default to 5 minutes.
+	Interval time.Duration
+	// OnError runs when a scheduled snapshot fails. It could be nil.
+	OnError func(err error)
+
+	cache *RateCache
+	mu    sync.Mutex
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewCacheSnapshotter create and return a CacheSnapshotter saving `cache` to `path`.
+func NewCacheSnapshotter(cache *RateCache, path string) *CacheSnapshotter {
+	return &CacheSnapshotter{
+		Path:  path,
+		cache: cache,
+	}
+}
+
+// Start saves a snapshot every Interval in the background. It does nothing if the CacheSnapshotter is already started.
+func (s *CacheSnapshotter) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// Stop stops the scheduled snapshots started by Start, and saves a last snapshot, e.g. on shutdown.
+func (s *CacheSnapshotter) Stop() error {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	return s.cache.Save(s.Path)
+}
+
+// run saves a snapshot every Interval until `stop` is closed, then closes `done`.
+func (s *CacheSnapshotter) run(stop, done chan struct{}) {
+	defer close(done)
+
+	interval := s.Interval
+	if interval <= 0 {
+		interval = defaultSnapshotInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.cache.Save(s.Path); err != nil && s.OnError != nil {
+				s.OnError(err)
+			}
+		}
+	}
+}
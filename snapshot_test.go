@@ -0,0 +1,132 @@
+package currconv
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateCache_SaveLoad(t *testing.T) {
+	now := time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)
+
+	cache := NewRateCache()
+	cache.SoftTTL = 10 * time.Minute
+	cache.now = func() time.Time { return now }
+	cache.store(compactRates{"USD_MYR": json.RawMessage("4.348493")}, now)
+	cache.store(compactRates{"EUR_MYR": json.RawMessage("4.6809")}, now.Add(-20*time.Minute))
+	cache.store(compactRates{"SGD_MYR": json.RawMessage("3.2786")}, now.Add(-2*time.Hour))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rates.json")
+	assert.NoError(t, cache.Save(path))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"version": 1`)
+	assert.Contains(t, string(b), `"fetchedAt": "2023-02-15T01:40:00Z"`)
+
+	restored := NewRateCache()
+	restored.SoftTTL = 10 * time.Minute
+	restored.now = func() time.Time { return now }
+	assert.NoError(t, restored.Load(path))
+	assert.Equal(t, 3, restored.Len())
+
+	rates := compactRates{}
+	missing, stale := restored.lookup([]string{"USD_MYR", "EUR_MYR", "SGD_MYR"}, rates)
+	assert.Equal(t, []string{"SGD_MYR"}, missing, "a restored rate expires at its original fetch time")
+	assert.Equal(t, []string{"EUR_MYR"}, stale)
+	assert.Equal(t, compactRates{"USD_MYR": json.RawMessage("4.348493"), "EUR_MYR": json.RawMessage("4.6809")}, rates)
+}
+
+func TestRateCache_ReadFrom(t *testing.T) {
+	now := time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)
+	seed := `{
+  "version": 1,
+  "savedAt": "2023-02-15T01:00:00Z",
+  "rates": {
+    "USD_MYR": {"rate": 4.3, "fetchedAt": "2023-02-15T01:00:00Z"},
+    "EUR_MYR": {"rate": 4.6, "fetchedAt": "2023-02-15T01:30:00Z"}
+  }
+}`
+
+	cache := NewRateCache()
+	cache.now = func() time.Time { return now }
+	cache.store(compactRates{"USD_MYR": json.RawMessage("4.348493")}, now)
+
+	_, err := cache.ReadFrom(strings.NewReader(seed))
+	assert.NoError(t, err)
+
+	rates := compactRates{}
+	missing, _ := cache.lookup([]string{"USD_MYR", "EUR_MYR"}, rates)
+	assert.Empty(t, missing)
+	assert.Equal(t, compactRates{"USD_MYR": json.RawMessage("4.348493"), "EUR_MYR": json.RawMessage("4.6")}, rates,
+		"a rate fetched after the seed is kept")
+
+	_, err = cache.ReadFrom(strings.NewReader(`{"version": 2, "rates": {}}`))
+	assert.EqualError(t, err, "unsupported cache snapshot version 2")
+
+	tests := []struct {
+		name     string
+		rate     string
+		errorMsg string
+	}{
+		{"Null rate", `{"rate": null, "fetchedAt": "2023-02-15T01:00:00Z"}`, "SGD_MYR: invalid rate null"},
+		{"String rate", `{"rate": "abc", "fetchedAt": "2023-02-15T01:00:00Z"}`, "SGD_MYR: invalid rate \"abc\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cache.ReadFrom(strings.NewReader(`{"version": 1, "rates": {"SGD_MYR": ` + tt.rate + `}}`))
+			assert.EqualError(t, err, tt.errorMsg)
+			assert.Equal(t, 2, cache.Len(), "a rejected snapshot loads no rate")
+		})
+	}
+
+	_, err = cache.ReadFrom(strings.NewReader(`{"version": 1, "rates": {
+		"SGD_MYR": {"rate": 3.2, "fetchedAt": "2023-02-15T02:03:00Z"},
+		"GBP_MYR": {"rate": 5.3, "fetchedAt": "2023-02-15T03:00:00Z"}
+	}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, cache.Len(), "a rate fetched too far in the future is skipped")
+
+	assert.Equal(t, now, cache.rates["SGD_MYR"].fetchedAt, "a rate within the clock skew is loaded as fetched now")
+
+	err = cache.Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestCacheSnapshotter(t *testing.T) {
+	now := time.Date(2023, 2, 15, 2, 0, 0, 0, time.UTC)
+	cache := NewRateCache()
+	cache.now = func() time.Time { return now }
+	cache.store(compactRates{"USD_MYR": json.RawMessage("4.348493")}, now)
+
+	path := filepath.Join(t.TempDir(), "rates.json")
+	s := NewCacheSnapshotter(cache, path)
+	s.Interval = 5 * time.Millisecond
+	s.OnError = func(err error) { assert.NoError(t, err) }
+
+	s.Start()
+	s.Start()
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, time.Millisecond, "a snapshot is saved on schedule")
+
+	cache.store(compactRates{"EUR_MYR": json.RawMessage("4.6809")}, now)
+	assert.NoError(t, s.Stop())
+
+	restored := NewRateCache()
+	assert.NoError(t, restored.Load(path))
+	assert.Equal(t, 2, restored.Len(), "a last snapshot is saved on stop")
+}
@@ -0,0 +1,146 @@
+package currconv
+
+import (
+	"context"
+	"net/http"
+)
+
+// Attribute keys of the spans created by API.
+const (
+	AttributeEndpoint   = "currconv.endpoint"
+	AttributePairs      = "currconv.pairs"
+	AttributeDate       = "currconv.date"
+	AttributeEndDate    = "currconv.end_date"
+	AttributeRows       = "currconv.rows"
+	AttributeCacheHit   = "currconv.cache_hit"
+	AttributeHTTPMethod = "http.request.method"
+	AttributeHTTPStatus = "http.response.status_code"
+	AttributeURL        = "url.full"
+)
+
+// Attribute is a key-value pair attached to a Span. Value is a string, an int or a bool.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Tracer creates spans, e.g. an adapter of an OpenTelemetry trace.Tracer and its propagator.
+// It must be safe for concurrent use.
+type Tracer interface {
+	// Start starts a span named `name` as a child of the span in `ctx`, and returns a context containing the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+	// Inject writes the trace context of the span in `ctx` to `header`, e.g. the W3C traceparent header.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a single operation of a trace, created by a Tracer.
+type Span interface {
+	// SetAttributes sets `attrs` on the span.
+	SetAttributes(attrs ...Attribute)
+	// RecordError records `err` and marks the span as failed.
+	RecordError(err error)
+	// End completes the span.
+	End()
+}
+
+// noopSpan is the Span used when Config.Tracer is nil.
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// WithContext returns a copy of the API which sends requests with `ctx`.
+// Spans are created as children of the span in `ctx`, and requests are canceled when `ctx` is done.
+func (a *API) WithContext(ctx context.Context) *API {
+	c := *a
+	c.ctx = ctx
+
+	return &c
+}
+
+// context returns the context of the API, default to context.Background.
+func (a *API) context() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+
+	return a.ctx
+}
+
+// startSpan starts the span of API method `name`, and returns a copy of `a` whose requests are children of the span.
+func (a *API) startSpan(name string, attrs ...Attribute) (*API, Span) {
+	if a.config.Tracer == nil {
+		return a, noopSpan{}
+	}
+
+	ctx, span := a.config.Tracer.Start(a.context(), "currconv."+name)
+	span.SetAttributes(attrs...)
+
+	return a.WithContext(ctx), span
+}
+
+// endSpan records `err` if any, with the API key redacted, and ends `span`.
+func (a *API) endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(a.redactError(err))
+	}
+	span.End()
+}
+
+// startAttempt starts the span of an HTTP attempt described by `info`, and injects its trace context into `header`.
+// API never retries a request, so each request has a single attempt span without a retry attribute.
+func (a *API) startAttempt(info RequestInfo, header http.Header) Span {
+	if a.config.Tracer == nil {
+		return noopSpan{}
+	}
+
+	ctx, span := a.config.Tracer.Start(a.context(), http.MethodGet+" "+info.Endpoint)
+	span.SetAttributes(Attribute{AttributeHTTPMethod, http.MethodGet}, Attribute{AttributeURL, info.URL})
+	span.SetAttributes(requestAttributes(info)...)
+	a.config.Tracer.Inject(ctx, header)
+
+	return span
+}
+
+// endAttempt sets the status code of `info` on `span`, records `err` if any, and ends `span`.
+func (a *API) endAttempt(span Span, info RequestInfo, err error) {
+	if info.StatusCode != 0 {
+		span.SetAttributes(Attribute{AttributeHTTPStatus, info.StatusCode})
+	}
+	a.endSpan(span, err)
+}
+
+// requestAttributes returns the endpoint, pair count and date range attributes of `info`.
+func requestAttributes(info RequestInfo) []Attribute {
+	attrs := []Attribute{{AttributeEndpoint, info.Endpoint}}
+	if info.Pairs > 0 {
+		attrs = append(attrs, Attribute{AttributePairs, info.Pairs})
+	}
+	if info.Date != "" {
+		attrs = append(attrs, Attribute{AttributeDate, info.Date})
+	}
+	if info.EndDate != "" {
+		attrs = append(attrs, Attribute{AttributeEndDate, info.EndDate})
+	}
+
+	return attrs
+}
+
+// convertAttributes returns the pair count attribute of a Convert API method.
+func convertAttributes(req ConvertRequest) []Attribute {
+	return []Attribute{{AttributePairs, len(req.Q)}}
+}
+
+// convertHistoricalAttributes returns the pair count and date range attributes of a ConvertHistorical API method.
+func convertHistoricalAttributes(req ConvertHistoricalRequest) []Attribute {
+	attrs := []Attribute{{AttributePairs, len(req.Q)}}
+	if !req.Date.IsZero() {
+		attrs = append(attrs, Attribute{AttributeDate, formatDate(req.Date)})
+	}
+	if !req.EndDate.IsZero() {
+		attrs = append(attrs, Attribute{AttributeEndDate, formatDate(req.EndDate)})
+	}
+
+	return attrs
+}
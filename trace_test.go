@@ -0,0 +1,229 @@
+package currconv
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// spanRecorder is an in-memory Tracer recording ended spans.
+type spanRecorder struct {
+	mu     sync.Mutex
+	nextID int
+	spans  []*recordedSpan
+}
+
+// recordedSpan is a Span created by spanRecorder.
+type recordedSpan struct {
+	recorder *spanRecorder
+	id       int
+	parent   int
+	name     string
+	attrs    map[string]interface{}
+	err      error
+}
+
+type spanKey struct{}
+
+func (r *spanRecorder) Start(ctx context.Context, name string) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	s := &recordedSpan{recorder: r, id: r.nextID, name: name, attrs: map[string]interface{}{}}
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		s.parent = parent.id
+	}
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (r *spanRecorder) Inject(ctx context.Context, header http.Header) {
+	if s, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		header.Set("traceparent", fmt.Sprintf("00-%032x-%016x-01", 1, s.id))
+	}
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *recordedSpan) End() {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.recorder.spans = append(s.recorder.spans, s)
+}
+
+// find returns the ended span named `name`.
+func (r *spanRecorder) find(name string) *recordedSpan {
+	for _, s := range r.spans {
+		if s.name == name {
+			return s
+		}
+	}
+
+	return nil
+}
+
+func TestTracer(t *testing.T) {
+	var traceparents []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		_, _ = w.Write([]byte(`{"USD_MYR": 4.348493}`))
+	}))
+	defer ts.Close()
+
+	r := &spanRecorder{}
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "secret",
+		Version: "v1",
+		Tracer:  r,
+	})
+
+	ctx, parent := r.Start(context.Background(), "handler")
+	_, err := api.WithContext(ctx).ConvertCompactExact(ConvertRequest{Q: []string{"USD_MYR"}})
+	assert.NoError(t, err)
+	parent.End()
+
+	assert.Len(t, r.spans, 3)
+
+	method := r.find("currconv.ConvertCompactExact")
+	assert.Equal(t, parent.(*recordedSpan).id, method.parent)
+	assert.Equal(t, map[string]interface{}{AttributePairs: 1}, method.attrs)
+	assert.NoError(t, method.err)
+
+	attempt := r.find("GET convert")
+	assert.Equal(t, method.id, attempt.parent)
+	assert.Equal(t, map[string]interface{}{
+		AttributeHTTPMethod: http.MethodGet,
+		AttributeURL:        ts.URL + "/api/v1/convert?apiKey=REDACTED&compact=ultra&q=USD_MYR",
+		AttributeEndpoint:   EndpointConvert,
+		AttributePairs:      1,
+		AttributeHTTPStatus: http.StatusOK,
+	}, attempt.attrs)
+
+	assert.Equal(t, []string{fmt.Sprintf("00-%032x-%016x-01", 1, attempt.id)}, traceparents)
+}
+
+func TestTracer_Nested(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"USD_MYR": {"2023-02-01": 4.3}, "EUR_MYR": {"2023-02-01": 4.6}}`))
+	}))
+	defer ts.Close()
+
+	r := &spanRecorder{}
+	api := NewAPI(Config{
+		BaseURL:            ts.URL,
+		APIKey:             "secret",
+		Version:            "v1",
+		MaxPairsPerRequest: 1,
+		Tracer:             r,
+	})
+
+	date := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	_, err := api.ConvertHistoricalMany(ConvertHistoricalRequest{Q: []string{"USD_MYR", "EUR_MYR"}, Date: date})
+	assert.NoError(t, err)
+
+	many := r.find("currconv.ConvertHistoricalMany")
+	assert.Zero(t, many.parent)
+	assert.Equal(t, map[string]interface{}{AttributePairs: 2, AttributeDate: "2023-02-01"}, many.attrs)
+
+	var methods, attempts int
+	for _, s := range r.spans {
+		switch s.name {
+		case "currconv.ConvertHistoricalCompactExact":
+			methods++
+			assert.Equal(t, many.id, s.parent)
+		case "GET convert":
+			attempts++
+			assert.Equal(t, "2023-02-01", s.attrs[AttributeDate])
+			assert.Equal(t, 1, s.attrs[AttributePairs])
+		}
+	}
+	assert.Equal(t, 2, methods)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestTracer_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status": 400, "error": "Invalid API key"}`))
+	}))
+	defer ts.Close()
+
+	r := &spanRecorder{}
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "secret",
+		Version: "v1",
+		Tracer:  r,
+	})
+
+	_, err := api.Currencies()
+	assert.EqualError(t, err, "Invalid API key")
+
+	assert.Len(t, r.spans, 2)
+	assert.EqualError(t, r.find("GET currencies").err, "Invalid API key")
+	assert.Equal(t, http.StatusBadRequest, r.find("GET currencies").attrs[AttributeHTTPStatus])
+	assert.EqualError(t, r.find("currconv.Currencies").err, "Invalid API key")
+
+	_, err = api.ConvertCompact(ConvertRequest{})
+	assert.Error(t, err)
+	assert.Len(t, r.spans, 3, "a request failing validation has no HTTP attempt")
+	assert.Error(t, r.find("currconv.ConvertCompact").err)
+}
+
+func TestTracer_NetworkError(t *testing.T) {
+	r := &spanRecorder{}
+	api := NewAPI(Config{
+		BaseURL: "/error/",
+		APIKey:  "secret",
+		Version: "v1",
+		Tracer:  r,
+	})
+
+	_, err := api.Usage()
+	assert.Error(t, err)
+
+	assert.Len(t, r.spans, 2)
+	for _, s := range r.spans {
+		assert.Error(t, s.err, s.name)
+		assert.NotContains(t, s.err.Error(), "secret", s.name)
+		assert.NotContains(t, fmt.Sprint(s.attrs), "secret", s.name)
+	}
+}
+
+func TestAPI_WithContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"timestamp": "2023-02-15T02:05:49.988Z", "usage": 1}`))
+	}))
+	defer ts.Close()
+
+	api := NewAPI(Config{
+		BaseURL: ts.URL,
+		APIKey:  "secret",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := api.WithContext(ctx).Usage()
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = api.Usage()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,208 @@
+// Package translation translates financial statements of foreign subsidiaries with the current-rate method.
+package translation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	currconv "github.com/kitloong/go-currency-converter-api/v2"
+)
+
+// Category decides which rate a trial balance line is translated at.
+type Category int
+
+const (
+	// BalanceSheet lines, assets and liabilities, are translated at the closing rate.
+	BalanceSheet Category = iota
+	// ProfitLoss lines, income and expenses, are translated at the period average rate.
+	ProfitLoss
+	// Equity lines are translated at the historical rate of their Date.
+	Equity
+)
+
+// defaultPlaces is the default number of digits after the decimal point of translated amounts.
+const defaultPlaces = 2
+
+// Line is a trial balance line in its functional currency.
+type Line struct {
+	Account  string
+	Category Category
+	// Amount is positive for debit and negative for credit.
+	Amount   currconv.Decimal
+	Currency string
+	// Date is the historical date of an Equity line, e.g. the date of a capital injection.
+	Date time.Time
+}
+
+// Request contains request fields of Translate.
+type Request struct {
+	// To is the presentation currency.
+	To string
+	// Lines is the trial balance, in one or more functional currencies.
+	Lines []Line
+	// Date is any date of the reporting period.
+	Date time.Time
+	// Period is the length of the reporting period.
+	Period currconv.Period
+	// BusinessDayAverage uses the business-day average rate for ProfitLoss lines instead of the simple average.
+	BusinessDayAverage bool
+	// Fallback is used when an Equity line's date has no rate.
+	Fallback currconv.FallbackPolicy
+	// Places is the number of digits after the decimal point of translated amounts, default to 2.
+	Places int32
+}
+
+// TranslatedLine is a Line translated into the presentation currency.
+type TranslatedLine struct {
+	Line
+	Rate       currconv.Decimal
+	RateDate   time.Time
+	Translated currconv.Decimal
+}
+
+// Result is the translated trial balance.
+type Result struct {
+	Lines []TranslatedLine
+	// CTA is the cumulative translation adjustment which balances the translated lines,
+	// positive for debit and negative for credit.
+	CTA currconv.Decimal
+	// Rates are the closing and average rates used, keyed by pair.
+	Rates map[string]currconv.AccountingRate
+}
+
+// Translator translates trial balances with rates from API.
+type Translator struct {
+	api *currconv.API
+}
+
+// New create and return a Translator.
+func New(api *currconv.API) *Translator {
+	return &Translator{
+		api,
+	}
+}
+
+// Translate translates `req.Lines` into `req.To`.
+// BalanceSheet lines are translated at the closing rate, ProfitLoss lines at the period average rate,
+// and Equity lines at the historical rate of their Date. The difference is reported as CTA.
+// The lines of each functional currency must sum to zero, so that CTA only holds the translation difference.
+func (t *Translator) Translate(req Request) (*Result, error) {
+	if req.To == "" {
+		return nil, errors.New("`To` is required")
+	}
+
+	if req.Date.IsZero() {
+		return nil, errors.New("`Date` is required")
+	}
+
+	places := req.Places
+	if places == 0 {
+		places = defaultPlaces
+	}
+
+	sums := map[string]currconv.Decimal{}
+	for _, l := range req.Lines {
+		sums[l.Currency] = sums[l.Currency].Add(l.Amount)
+	}
+
+	currencies := make([]string, 0, len(sums))
+	for currency := range sums {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	for _, currency := range currencies {
+		if !sums[currency].IsZero() {
+			return nil, fmt.Errorf("lines in %s do not balance, they sum to %s", currency, sums[currency])
+		}
+	}
+
+	var pairs []string
+	var equity []currconv.HistoricalAmount
+	seen := map[string]bool{}
+	for _, l := range req.Lines {
+		if l.Category == Equity {
+			if l.Date.IsZero() {
+				return nil, fmt.Errorf("`Date` of equity line %q is required", l.Account)
+			}
+			equity = append(equity, currconv.HistoricalAmount{Amount: l.Amount, Currency: l.Currency, Date: l.Date})
+			continue
+		}
+
+		if pair := l.Currency + "_" + req.To; l.Currency != req.To && !seen[pair] {
+			pairs = append(pairs, pair)
+			seen[pair] = true
+		}
+	}
+
+	result := &Result{Rates: map[string]currconv.AccountingRate{}}
+	if len(pairs) > 0 {
+		rates, err := t.api.AccountingRates(currconv.AccountingRatesRequest{
+			Q:      pairs,
+			Date:   req.Date,
+			Period: req.Period,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rates {
+			result.Rates[r.Pair] = r
+		}
+	}
+
+	var historical []currconv.ConvertedAmount
+	if len(equity) > 0 {
+		var err error
+		historical, err = t.api.ConvertHistoricalBulk(currconv.ConvertHistoricalBulkRequest{
+			To:       req.To,
+			Rows:     equity,
+			Fallback: req.Fallback,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var total currconv.Decimal
+	for _, l := range req.Lines {
+		tl := TranslatedLine{Line: l}
+
+		switch {
+		case l.Category == Equity:
+			h := historical[0]
+			historical = historical[1:]
+			if h.Err != nil {
+				return nil, fmt.Errorf("equity line %q: %w", l.Account, h.Err)
+			}
+			tl.Rate, tl.RateDate = h.Rate, h.RateDate
+		case l.Currency == req.To:
+			tl.Rate = currconv.NewDecimalFromInt(1)
+		default:
+			r, ok := result.Rates[l.Currency+"_"+req.To]
+			if !ok {
+				return nil, fmt.Errorf("line %q: %w", l.Account, currconv.ErrRateNotFound)
+			}
+
+			tl.Rate, tl.RateDate = r.Closing, r.ClosingDate
+			if l.Category == ProfitLoss {
+				tl.Rate, tl.RateDate = r.Average, r.End
+				if req.BusinessDayAverage {
+					if r.BusinessDays == 0 {
+						return nil, fmt.Errorf("line %q: %w", l.Account, currconv.ErrRateNotFound)
+					}
+					tl.Rate = r.BusinessDayAverage
+				}
+			}
+		}
+
+		tl.Translated = l.Amount.Mul(tl.Rate).Round(places)
+		total = total.Add(tl.Translated)
+		result.Lines = append(result.Lines, tl)
+	}
+
+	result.CTA = total.Neg()
+	return result, nil
+}
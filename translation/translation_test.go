@@ -0,0 +1,181 @@
+package translation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	currconv "github.com/kitloong/go-currency-converter-api/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// historicalServer serves compact historical rates from `rates`, keyed by pair and date.
+func historicalServer(rates map[string]map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		start, _ := time.Parse("2006-01-02", q.Get("date"))
+		end := start
+		if q.Get("endDate") != "" {
+			end, _ = time.Parse("2006-01-02", q.Get("endDate"))
+		}
+
+		result := map[string]map[string]json.RawMessage{}
+		for _, pair := range strings.Split(q.Get("q"), ",") {
+			result[pair] = map[string]json.RawMessage{}
+			for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+				if rate, ok := rates[pair][d.Format("2006-01-02")]; ok {
+					result[pair][d.Format("2006-01-02")] = json.RawMessage(rate)
+				}
+			}
+		}
+
+		b, _ := json.Marshal(result)
+		_, _ = w.Write(b)
+	}))
+}
+
+func TestTranslator_Translate(t *testing.T) {
+	rates := map[string]string{"2020-01-01": "0.20"}
+	for d := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC); d.Month() == time.January; d = d.AddDate(0, 0, 1) {
+		rates[d.Format("2006-01-02")] = "0.24"
+	}
+	rates["2023-01-31"] = "0.25"
+
+	ts := historicalServer(map[string]map[string]string{"MYR_USD": rates})
+	defer ts.Close()
+
+	translator := New(currconv.NewAPI(currconv.Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	}))
+
+	result, err := translator.Translate(Request{
+		To:   "USD",
+		Date: time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC),
+		Lines: []Line{
+			{Account: "Cash", Category: BalanceSheet, Amount: currconv.MustParseDecimal("1000"), Currency: "MYR"},
+			{Account: "Loan", Category: BalanceSheet, Amount: currconv.MustParseDecimal("-400"), Currency: "MYR"},
+			{Account: "Share capital", Category: Equity, Amount: currconv.MustParseDecimal("-500"), Currency: "MYR", Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Account: "Revenue", Category: ProfitLoss, Amount: currconv.MustParseDecimal("-300"), Currency: "MYR"},
+			{Account: "Expense", Category: ProfitLoss, Amount: currconv.MustParseDecimal("200"), Currency: "MYR"},
+			{Account: "Intercompany", Category: BalanceSheet, Amount: currconv.MustParseDecimal("0"), Currency: "USD"},
+		},
+	})
+	assert.NoError(t, err)
+
+	translated := make([]string, 0, len(result.Lines))
+	for _, l := range result.Lines {
+		translated = append(translated, l.Translated.String())
+	}
+	assert.Equal(t, []string{"250.00", "-100.00", "-100.00", "-72.10", "48.06", "0.00"}, translated)
+
+	assert.Equal(t, "0.25", result.Lines[0].Rate.String())
+	assert.Equal(t, time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC), result.Lines[0].RateDate)
+	assert.Equal(t, "0.20", result.Lines[2].Rate.String())
+	assert.Equal(t, "0.240323", result.Lines[3].Rate.String())
+	assert.Equal(t, "1", result.Lines[5].Rate.String())
+	assert.Equal(t, "-25.96", result.CTA.String())
+	assert.Equal(t, "2023-01", result.Rates["MYR_USD"].Period)
+}
+
+func TestTranslator_TranslateError(t *testing.T) {
+	ts := historicalServer(map[string]map[string]string{})
+	defer ts.Close()
+
+	translator := New(currconv.NewAPI(currconv.Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	}))
+
+	tests := []struct {
+		name     string
+		req      Request
+		errorMsg string
+	}{
+		{
+			"`To` is required",
+			Request{},
+			"`To` is required",
+		},
+		{
+			"`Date` is required",
+			Request{To: "USD"},
+			"`Date` is required",
+		},
+		{
+			"Equity date is required",
+			Request{
+				To:    "USD",
+				Date:  time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC),
+				Lines: []Line{{Account: "Share capital", Category: Equity, Currency: "MYR"}},
+			},
+			"`Date` of equity line \"Share capital\" is required",
+		},
+		{
+			"Unbalanced",
+			Request{
+				To:   "USD",
+				Date: time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC),
+				Lines: []Line{
+					{Account: "Cash", Category: BalanceSheet, Amount: currconv.MustParseDecimal("1000"), Currency: "MYR"},
+					{Account: "Revenue", Category: ProfitLoss, Amount: currconv.MustParseDecimal("-900"), Currency: "MYR"},
+					{Account: "Bank", Category: BalanceSheet, Amount: currconv.MustParseDecimal("0"), Currency: "USD"},
+				},
+			},
+			"lines in MYR do not balance, they sum to 100",
+		},
+		{
+			"Closing rate not found",
+			Request{
+				To:    "USD",
+				Date:  time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC),
+				Lines: []Line{{Account: "Cash", Category: BalanceSheet, Currency: "MYR"}},
+			},
+			"line \"Cash\": rate not found",
+		},
+		{
+			"Historical rate not found",
+			Request{
+				To:    "USD",
+				Date:  time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC),
+				Lines: []Line{{Account: "Share capital", Category: Equity, Currency: "MYR", Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}},
+			},
+			"equity line \"Share capital\": rate not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := translator.Translate(tt.req)
+			assert.EqualError(t, err, tt.errorMsg)
+		})
+	}
+}
+
+func TestTranslator_TranslateNoBusinessDay(t *testing.T) {
+	ts := historicalServer(map[string]map[string]string{"MYR_USD": {"2023-01-01": "0.24", "2023-01-07": "0.25"}})
+	defer ts.Close()
+
+	translator := New(currconv.NewAPI(currconv.Config{
+		BaseURL: ts.URL,
+		APIKey:  "key",
+		Version: "v1",
+	}))
+
+	_, err := translator.Translate(Request{
+		To:                 "USD",
+		Date:               time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC),
+		BusinessDayAverage: true,
+		Lines: []Line{
+			{Account: "Revenue", Category: ProfitLoss, Amount: currconv.MustParseDecimal("-300"), Currency: "MYR"},
+			{Account: "Expense", Category: ProfitLoss, Amount: currconv.MustParseDecimal("300"), Currency: "MYR"},
+		},
+	})
+	assert.ErrorIs(t, err, currconv.ErrRateNotFound, "weekend rates only have no business day average")
+	assert.EqualError(t, err, "line \"Revenue\": rate not found")
+}
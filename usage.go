@@ -13,5 +13,8 @@ type Usage struct {
 
 // Usage returns your current API usage.
 func (a *API) Usage() (result *Usage, err error) {
+	a, span := a.startSpan("Usage")
+	defer func() { a.endSpan(span, err) }()
+
 	return call[Usage](a, false, "others/usage", func(q url.Values) error { return nil })
 }